@@ -0,0 +1,179 @@
+package awx
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	awxgo "github.com/davidfischer-ch/awx-go"
+)
+
+// APIError is returned when AWX answers with a status outside of [200, 300).
+type APIError struct {
+	Method     string
+	Endpoint   string
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s %s responded with %d: %s", e.Method, e.Endpoint, e.StatusCode, e.Body)
+}
+
+// isNotFound reports whether err is an AWX 404 answer.
+func isNotFound(err error) bool {
+	if e, ok := err.(*APIError); ok {
+		return e.StatusCode == http.StatusNotFound
+	}
+	return false
+}
+
+// apiRequest sends data (if any) as JSON to endpoint and decodes the answer into result (if any).
+func (c *Client) apiRequest(method, endpoint string, data interface{}, result interface{}, params map[string]string) error {
+	var payload io.Reader
+	if data != nil {
+		b, err := json.Marshal(data)
+		if err != nil {
+			return err
+		}
+		payload = bytes.NewReader(b)
+	}
+
+	ar := awxgo.NewAPIRequest(method, endpoint, payload)
+	ar.SetHeader("Content-Type", "application/json")
+
+	var body string
+	resp, err := c.Requester.Do(ar, &body, params)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &APIError{Method: method, Endpoint: endpoint, StatusCode: resp.StatusCode, Body: body}
+	}
+	if result == nil || body == "" {
+		return nil
+	}
+	return json.Unmarshal([]byte(body), result)
+}
+
+func (c *Client) apiGet(endpoint string, result interface{}, params map[string]string) error {
+	return c.apiRequest("GET", endpoint, nil, result, params)
+}
+
+func (c *Client) apiPost(endpoint string, data interface{}, result interface{}) error {
+	return c.apiRequest("POST", endpoint, data, result, nil)
+}
+
+func (c *Client) apiPatch(endpoint string, data interface{}, result interface{}) error {
+	return c.apiRequest("PATCH", endpoint, data, result, nil)
+}
+
+func (c *Client) apiDelete(endpoint string) error {
+	return c.apiRequest("DELETE", endpoint, nil, nil, nil)
+}
+
+// apiList walks every page of a list endpoint and decodes all the results into results (a pointer to a slice).
+func (c *Client) apiList(endpoint string, params map[string]string, results interface{}) error {
+	query := map[string]string{"page_size": "200"}
+	for k, v := range params {
+		query[k] = v
+	}
+
+	all := []json.RawMessage{}
+	for page := 1; ; page++ {
+		query["page"] = strconv.Itoa(page)
+		var res struct {
+			awxgo.Pagination
+			Results []json.RawMessage `json:"results"`
+		}
+		if err := c.apiGet(endpoint, &res, query); err != nil {
+			return err
+		}
+		all = append(all, res.Results...)
+		if res.Next == nil || len(res.Results) == 0 {
+			break
+		}
+	}
+
+	b, err := json.Marshal(all)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, results)
+}
+
+// apiAssociate attaches the object id to a related list endpoint (e.g. /api/v2/job_templates/1/labels/).
+func (c *Client) apiAssociate(endpoint string, id int) error {
+	return c.apiPost(endpoint, map[string]interface{}{"id": id}, nil)
+}
+
+// apiDisassociate detaches the object id from a related list endpoint.
+func (c *Client) apiDisassociate(endpoint string, id int) error {
+	return c.apiPost(endpoint, map[string]interface{}{"id": id, "disassociate": true}, nil)
+}
+
+// apiListIDs returns the IDs of the objects listed by a related list endpoint.
+func (c *Client) apiListIDs(endpoint string) ([]int, error) {
+	var objects []struct {
+		ID int `json:"id"`
+	}
+	if err := c.apiList(endpoint, nil, &objects); err != nil {
+		return nil, err
+	}
+	ids := make([]int, 0, len(objects))
+	for _, o := range objects {
+		ids = append(ids, o.ID)
+	}
+	return ids, nil
+}
+
+// apiReconcile associates and disassociates objects of a related list endpoint so that it lists exactly wanted.
+// Objects that vanished in the meantime (e.g. an orphaned label AWX garbage collected) are ignored.
+func (c *Client) apiReconcile(endpoint string, wanted []int) error {
+	current, err := c.apiListIDs(endpoint)
+	if err != nil {
+		return err
+	}
+	for _, id := range current {
+		if !intInSlice(id, wanted) {
+			if err := c.apiDisassociate(endpoint, id); err != nil && !isNotFound(err) {
+				return err
+			}
+		}
+	}
+	for _, id := range wanted {
+		if !intInSlice(id, current) {
+			if err := c.apiAssociate(endpoint, id); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// apiReconcileOrdered is apiReconcile for related list endpoints whose order matters (e.g. instance groups).
+// AWX appends associated objects, so everything after the longest common prefix is detached and re-attached in order.
+func (c *Client) apiReconcileOrdered(endpoint string, wanted []int) error {
+	current, err := c.apiListIDs(endpoint)
+	if err != nil {
+		return err
+	}
+	prefix := 0
+	for prefix < len(current) && prefix < len(wanted) && current[prefix] == wanted[prefix] {
+		prefix++
+	}
+	for _, id := range current[prefix:] {
+		if err := c.apiDisassociate(endpoint, id); err != nil && !isNotFound(err) {
+			return err
+		}
+	}
+	for _, id := range wanted[prefix:] {
+		if err := c.apiAssociate(endpoint, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package awx
 import (
 	"crypto/tls"
 	"net/http"
+	"sync"
 
 	awxgo "github.com/davidfischer-ch/awx-go"
 )
@@ -15,8 +16,19 @@ type Config struct {
 	SslSkipVerify bool
 }
 
+// Client wraps the awx-go services and keeps a requester around for the
+// endpoints awx-go does not implement yet.
+type Client struct {
+	*awxgo.AWX
+	Requester *awxgo.Requester
+
+	versionOnce sync.Once
+	version     string
+	versionErr  error
+}
+
 // Client for Tower/AWX API v2
-func (c *Config) Client() *awxgo.AWX {
+func (c *Config) Client() *Client {
 	tr := &http.Transport{
 		TLSClientConfig: &tls.Config{InsecureSkipVerify: c.SslSkipVerify},
 	}
@@ -25,5 +37,12 @@ func (c *Config) Client() *awxgo.AWX {
 
 	awx := awxgo.NewAWX(c.Endpoint, c.Username, c.Password, client)
 
-	return awx
+	return &Client{
+		AWX: awx,
+		Requester: &awxgo.Requester{
+			Base:      c.Endpoint,
+			BasicAuth: &awxgo.BasicAuth{Username: c.Username, Password: c.Password},
+			Client:    client,
+		},
+	}
 }
@@ -26,7 +26,7 @@ func dataSourceHost() *schema.Resource {
 }
 
 func dataSourceHostRead(d *schema.ResourceData, meta interface{}) error {
-	awx := meta.(*awxgo.AWX)
+	awx := meta.(*Client)
 	awxService := awx.HostService
 	_, res, err := awxService.ListHosts(map[string]string{
 		"name": d.Get("name").(string)})
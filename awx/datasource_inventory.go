@@ -26,7 +26,7 @@ func dataSourceInventory() *schema.Resource {
 }
 
 func dataSourceInventoryRead(d *schema.ResourceData, meta interface{}) error {
-	awx := meta.(*awxgo.AWX)
+	awx := meta.(*Client)
 	awxService := awx.InventoriesService
 	_, res, err := awxService.ListInventories(map[string]string{
 		"name": d.Get("name").(string)})
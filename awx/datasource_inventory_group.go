@@ -31,7 +31,7 @@ func dataSourceInventoryGroup() *schema.Resource {
 }
 
 func dataSourceInventoryGroupRead(d *schema.ResourceData, meta interface{}) error {
-	awx := meta.(*awxgo.AWX)
+	awx := meta.(*Client)
 	awxService := awx.GroupService
 	_, res, err := awxService.ListGroups(map[string]string{
 		"name": d.Get("name").(string)})
@@ -0,0 +1,203 @@
+package awx
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	awxgo "github.com/davidfischer-ch/awx-go"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceInventoryRendered() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceInventoryRenderedRead,
+		Schema: map[string]*schema.Schema{
+			"inventory_id": &schema.Schema{
+				Type:        schema.TypeInt,
+				Required:    true,
+				Description: "Numeric ID of the inventory to render",
+			},
+			"groups": &schema.Schema{
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: "Only render these groups, their descendants and their hosts",
+			},
+			"exclude_disabled_hosts": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Leave out the hosts that are not enabled",
+			},
+			"json": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Inventory in the JSON format of Ansible dynamic inventory scripts",
+			},
+			"yaml": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Inventory in the Ansible YAML format",
+			},
+			"ini": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Inventory in the Ansible INI format",
+			},
+			"hosts": &schema.Schema{
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Names of the rendered hosts",
+			},
+		},
+	}
+}
+
+func dataSourceInventoryRenderedRead(d *schema.ResourceData, meta interface{}) error {
+	awx := meta.(*Client)
+	id := d.Get("inventory_id").(int)
+	inventory := new(awxgo.Inventory)
+	if err := awx.apiGet(fmt.Sprintf("/api/v2/inventories/%d/", id), inventory, nil); err != nil {
+		return err
+	}
+	endpoint := fmt.Sprintf("/api/v2/inventories/%d/script/", id)
+	if inventory.Related != nil && inventory.Related.Script != "" {
+		endpoint = inventory.Related.Script
+	}
+
+	// AWX only lists the enabled hosts unless asked for all of them
+	params := map[string]string{"hostvars": "1"}
+	if !d.Get("exclude_disabled_hosts").(bool) {
+		params["all"] = "1"
+	}
+	var script map[string]json.RawMessage
+	if err := awx.apiGet(endpoint, &script, params); err != nil {
+		return err
+	}
+	layout, err := parseInventoryScript(script)
+	if err != nil {
+		return fmt.Errorf("Script of inventory %d: %s", id, err)
+	}
+	if groups := expandStringSet(d.Get("groups")); len(groups) > 0 {
+		if layout, err = filterInventoryLayout(layout, groups); err != nil {
+			return fmt.Errorf("Inventory %d: %s", id, err)
+		}
+	}
+
+	rendered, err := renderInventoryJSON(layout)
+	if err != nil {
+		return err
+	}
+	d.Set("json", rendered)
+	if rendered, err = renderInventoryYAML(layout); err != nil {
+		return err
+	}
+	d.Set("yaml", rendered)
+	d.Set("ini", renderInventoryINI(layout))
+	d.Set("hosts", sortedHostNames(layout.Hosts))
+	d.SetId(strconv.Itoa(id))
+	return nil
+}
+
+type inventoryScriptGroup struct {
+	Hosts    []string               `json:"hosts"`
+	Children []string               `json:"children"`
+	Vars     map[string]interface{} `json:"vars"`
+}
+
+// parseInventoryScript converts the output of an Ansible dynamic inventory script (with _meta.hostvars) to a layout.
+func parseInventoryScript(script map[string]json.RawMessage) (*inventoryLayout, error) {
+	layout := newInventoryLayout()
+	if raw, ok := script["_meta"]; ok {
+		var meta struct {
+			Hostvars map[string]map[string]interface{} `json:"hostvars"`
+		}
+		if err := json.Unmarshal(raw, &meta); err != nil {
+			return nil, fmt.Errorf("_meta: %s", err)
+		}
+		for name, variables := range meta.Hostvars {
+			h := layout.host(name)
+			for k, v := range variables {
+				h.Variables[k] = normalizeParsed(v)
+			}
+		}
+	}
+	for name, raw := range script {
+		if name == "_meta" {
+			continue
+		}
+		group := new(inventoryScriptGroup)
+		if err := json.Unmarshal(raw, group); err != nil {
+			// Scripts may also list the hosts of a group directly
+			if err := json.Unmarshal(raw, &group.Hosts); err != nil {
+				return nil, fmt.Errorf("Group %s: %s", name, err)
+			}
+		}
+		if isImplicitGroup(name) {
+			if name == "all" && len(group.Vars) > 0 {
+				layout.Variables = normalizeParsed(group.Vars).(map[string]interface{})
+			}
+			for _, host := range group.Hosts {
+				layout.host(host)
+			}
+			continue
+		}
+		g := layout.group(name)
+		for k, v := range group.Vars {
+			g.Variables[k] = normalizeParsed(v)
+		}
+		for _, child := range group.Children {
+			if !isImplicitGroup(child) {
+				layout.group(child)
+				g.Children = append(g.Children, child)
+			}
+		}
+		for _, host := range group.Hosts {
+			h := layout.host(host)
+			h.Groups = append(h.Groups, name)
+		}
+	}
+	layout.normalize()
+	return layout, nil
+}
+
+// filterInventoryLayout keeps the given groups, their descendants and the hosts member of one of them.
+func filterInventoryLayout(layout *inventoryLayout, groups []string) (*inventoryLayout, error) {
+	kept := map[string]bool{}
+	pending := []string{}
+	for _, name := range groups {
+		if _, ok := layout.Groups[name]; !ok {
+			return nil, fmt.Errorf("group %s not found", name)
+		}
+		pending = append(pending, name)
+	}
+	for len(pending) > 0 {
+		name := pending[0]
+		pending = pending[1:]
+		if !kept[name] {
+			kept[name] = true
+			pending = append(pending, layout.Groups[name].Children...)
+		}
+	}
+
+	filtered := newInventoryLayout()
+	filtered.Variables = layout.Variables
+	for name := range kept {
+		g := filtered.group(name)
+		g.Variables = layout.Groups[name].Variables
+		g.Children = layout.Groups[name].Children
+	}
+	for name, h := range layout.Hosts {
+		for _, group := range h.Groups {
+			if kept[group] {
+				host := filtered.host(name)
+				host.Variables = h.Variables
+				host.Groups = append(host.Groups, group)
+			}
+		}
+	}
+	return filtered, nil
+}
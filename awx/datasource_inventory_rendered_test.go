@@ -0,0 +1,168 @@
+package awx
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+)
+
+// awx_inventory_rendered test case, against a local stub of the inventory endpoints
+func TestAWXInventoryRendered(t *testing.T) {
+	stub := newInventoryStub(1)
+	defer stub.Close()
+	stub.variables = "env: production\n"
+	datacenter := stub.addGroup("datacenter", "")
+	web := stub.addGroup("web", `{"http_port": 80}`)
+	db := stub.addGroup("db", "")
+	stub.groups[datacenter].Children[web] = true
+	stub.groups[web].Hosts[stub.addHost("web1", `{"ansible_host": "10.0.0.1"}`)] = true
+	disabled := stub.addHost("web2", "")
+	stub.hosts[disabled].Enabled = false
+	stub.groups[web].Hosts[disabled] = true
+	stub.groups[db].Hosts[stub.addHost("db1", "")] = true
+	stub.addHost("mail", "")
+
+	provider := fmt.Sprintf("provider \"awx\" {\n\tendpoint = %q\n}\n", stub.URL)
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: provider + testAWXInventoryRenderedConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.awx_inventory_rendered.all", "ini", testAWXInventoryRenderedINI),
+					resource.TestCheckResourceAttr("data.awx_inventory_rendered.all", "hosts.#", "4"),
+					resource.TestCheckResourceAttr("data.awx_inventory_rendered.datacenter", "yaml", testAWXInventoryRenderedYAML),
+					resource.TestCheckResourceAttr("data.awx_inventory_rendered.datacenter", "json", testAWXInventoryRenderedJSON),
+				),
+			},
+			{
+				Config:      provider + testAWXInventoryRenderedUnknownGroupConfig,
+				ExpectError: regexp.MustCompile("Inventory 1: group webs not found"),
+			},
+		},
+	})
+}
+
+func TestParseInventoryScript(t *testing.T) {
+	layout, err := parseInventoryFile("ini", testAWXInventoryRenderedINI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rendered, err := renderInventoryJSON(layout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var script map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(rendered), &script); err != nil {
+		t.Fatal(err)
+	}
+	if parsed, err := parseInventoryScript(script); err != nil || !reflect.DeepEqual(parsed, layout) {
+		t.Fatalf("JSON rendering does not round-trip (%v)\n%s", err, rendered)
+	}
+
+	// Scripts may list the hosts of a group without the hosts key
+	script = nil
+	if err := json.Unmarshal([]byte(`{"web": ["web1", "web2"], "all": {"vars": {"a": 1}}}`), &script); err != nil {
+		t.Fatal(err)
+	}
+	expected := newInventoryLayout()
+	expected.Variables = map[string]interface{}{"a": float64(1)}
+	expected.group("web")
+	expected.host("web1").Groups = []string{"web"}
+	expected.host("web2").Groups = []string{"web"}
+	if parsed, err := parseInventoryScript(script); err != nil || !reflect.DeepEqual(parsed, expected) {
+		t.Fatalf("Unexpected layout (%v)\n%s", err, renderInventoryINI(parsed))
+	}
+}
+
+const testAWXInventoryRenderedConfig = `
+data "awx_inventory_rendered" "all" {
+	inventory_id = 1
+}
+
+data "awx_inventory_rendered" "datacenter" {
+	inventory_id           = 1
+	groups                 = ["datacenter"]
+	exclude_disabled_hosts = true
+}
+`
+
+const testAWXInventoryRenderedUnknownGroupConfig = `
+data "awx_inventory_rendered" "all" {
+	inventory_id = 1
+	groups       = ["webs"]
+}
+`
+
+const testAWXInventoryRenderedINI = `mail
+
+[all:vars]
+env=production
+
+[datacenter]
+
+[datacenter:children]
+web
+
+[db]
+db1
+
+[web]
+web1 ansible_host=10.0.0.1
+web2
+
+[web:vars]
+http_port=80
+`
+
+const testAWXInventoryRenderedYAML = `all:
+  vars:
+    env: production
+  children:
+    datacenter:
+      children:
+        web: {}
+    web:
+      hosts:
+        web1:
+          ansible_host: 10.0.0.1
+      vars:
+        http_port: 80
+`
+
+const testAWXInventoryRenderedJSON = `{
+  "_meta": {
+    "hostvars": {
+      "web1": {
+        "ansible_host": "10.0.0.1"
+      }
+    }
+  },
+  "all": {
+    "children": [
+      "datacenter"
+    ],
+    "vars": {
+      "env": "production"
+    }
+  },
+  "datacenter": {
+    "children": [
+      "web"
+    ]
+  },
+  "web": {
+    "hosts": [
+      "web1"
+    ],
+    "vars": {
+      "http_port": 80
+    }
+  }
+}
+`
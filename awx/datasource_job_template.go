@@ -31,7 +31,7 @@ func dataSourceJobTemplate() *schema.Resource {
 }
 
 func dataSourceJobTemplateRead(d *schema.ResourceData, meta interface{}) error {
-	awx := meta.(*awxgo.AWX)
+	awx := meta.(*Client)
 	awxService := awx.JobTemplateService
 	_, res, err := awxService.ListJobTemplates(map[string]string{
 		"name": d.Get("name").(string)})
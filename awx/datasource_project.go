@@ -26,7 +26,7 @@ func dataSourceProjectObject() *schema.Resource {
 }
 
 func dataSourceProjectObjectRead(d *schema.ResourceData, meta interface{}) error {
-	awx := meta.(*awxgo.AWX)
+	awx := meta.(*Client)
 	awxService := awx.ProjectService
 	_, res, err := awxService.ListProjects(map[string]string{
 		"name": d.Get("name").(string)})
@@ -3,23 +3,19 @@ package awx
 import (
 	"encoding/json"
 	"fmt"
+	"reflect"
+	"sort"
 
-	awxgo "github.com/davidfischer-ch/awx-go"
 	"github.com/hashicorp/terraform/helper/schema"
 	"gopkg.in/yaml.v2"
 )
 
-func normalizeJSONYaml(s interface{}) string {
-	result := ""
-	if j, ok := normalizeJSONOk(s); ok {
-		result = j
-	} else if y, ok := normalizeYamlOk(s); ok {
-		result = y
-	} else {
-		result = s.(string)
-	}
-	return result
+// resourceGetter is implemented by both schema.ResourceData and schema.ResourceDiff.
+type resourceGetter interface {
+	Get(string) interface{}
+	GetOk(string) (interface{}, bool)
 }
+
 func normalizeJSONOk(s interface{}) (string, bool) {
 	if s == nil || s == "" {
 		return "", true
@@ -38,26 +34,80 @@ func normalizeJSON(s interface{}) string {
 	return v
 }
 
-func normalizeYamlOk(s interface{}) (string, bool) {
-	if s == nil || s == "" {
-		return "", true
+// parseJSONYaml decodes a JSON or YAML document into comparable values: string keys, float64 numbers, an empty
+// document is an empty mapping.
+func parseJSONYaml(s string) (interface{}, error) {
+	var value interface{}
+	if err := json.Unmarshal([]byte(s), &value); err != nil {
+		if yamlErr := yaml.Unmarshal([]byte(s), &value); yamlErr != nil {
+			return nil, fmt.Errorf("not valid JSON (%s) nor YAML (%s)", err, yamlErr)
+		}
 	}
-	var j interface{}
-	err := yaml.Unmarshal([]byte(s.(string)), &j)
+	if value == nil {
+		return map[string]interface{}{}, nil
+	}
+	return normalizeParsed(value), nil
+}
+
+func normalizeParsed(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			m[fmt.Sprint(key)] = normalizeParsed(item)
+		}
+		return m
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			m[key] = normalizeParsed(item)
+		}
+		return m
+	case []interface{}:
+		l := make([]interface{}, len(v))
+		for i, item := range v {
+			l[i] = normalizeParsed(item)
+		}
+		return l
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	default:
+		return v
+	}
+}
+
+// validateJSONYaml checks the value is a JSON or YAML mapping (or empty).
+func validateJSONYaml(v interface{}, k string) (ws []string, errors []error) {
+	value, err := parseJSONYaml(v.(string))
 	if err != nil {
-		return fmt.Sprintf("Error parsing YAML: %s", err), false
+		errors = append(errors, fmt.Errorf("%q: %s", k, err))
+		return
 	}
-	b, _ := yaml.Marshal(j)
-	return string(b[:]), true
+	if _, ok := value.(map[string]interface{}); !ok {
+		errors = append(errors, fmt.Errorf("%q: must be a JSON or YAML mapping", k))
+	}
+	return
 }
 
-func normalizeYaml(s interface{}) string {
-	v, _ := normalizeYamlOk(s)
-	return v
+// suppressEquivalentJSONYaml ignores the differences of formatting, key order, comments and syntax (JSON or YAML).
+func suppressEquivalentJSONYaml(k, old, new string, d *schema.ResourceData) bool {
+	o, err := parseJSONYaml(old)
+	if err != nil {
+		return false
+	}
+	n, err := parseJSONYaml(new)
+	if err != nil {
+		return false
+	}
+	return reflect.DeepEqual(o, n)
 }
 
 func getRoleID(d *schema.ResourceData, m interface{}) (int, error) {
-	awx := m.(*awxgo.AWX)
+	awx := m.(*Client)
 	switch d.Get("resource_type").(string) {
 	case "inventory":
 		awxService := awx.InventoriesService
@@ -171,3 +221,112 @@ func getRoleID(d *schema.ResourceData, m interface{}) (int, error) {
 	}
 	return 0, fmt.Errorf("Not implemented API endpoint")
 }
+
+// expandIDSet converts a set of numeric IDs to a slice.
+func expandIDSet(raw interface{}) []int {
+	ids := []int{}
+	if set, ok := raw.(*schema.Set); ok {
+		for _, id := range set.List() {
+			ids = append(ids, id.(int))
+		}
+	}
+	return ids
+}
+
+// expandStringSet converts a set of strings to a slice.
+func expandStringSet(raw interface{}) []string {
+	values := []string{}
+	if set, ok := raw.(*schema.Set); ok {
+		for _, value := range set.List() {
+			values = append(values, value.(string))
+		}
+	}
+	return values
+}
+
+// expandIDList converts an ordered list of numeric IDs to a slice.
+func expandIDList(raw interface{}) []int {
+	ids := []int{}
+	if list, ok := raw.([]interface{}); ok {
+		for _, id := range list {
+			ids = append(ids, id.(int))
+		}
+	}
+	return ids
+}
+
+// relatedIDsManaged reports whether an authoritative list of related objects is managed, that is it was set in the
+// configuration and is tracked in the state (even once emptied). Unset lists are neither refreshed nor reconciled so
+// the objects related out of band are kept.
+func relatedIDsManaged(d *schema.ResourceData, key string) bool {
+	_, ok := d.GetOkExists(key)
+	return ok
+}
+
+// readRelatedIDs sets key of the resource from the related list endpoint, if it is managed.
+func readRelatedIDs(awx *Client, d *schema.ResourceData, key, endpoint string) error {
+	if !relatedIDsManaged(d, key) {
+		return nil
+	}
+	ids, err := awx.apiListIDs(endpoint)
+	if err != nil {
+		return err
+	}
+	d.Set(key, ids)
+	return nil
+}
+
+// closeMatches returns up to n candidates close to word (by edit distance), the closest first.
+func closeMatches(word string, candidates []string, n int) []string {
+	threshold := len(word) / 3
+	if threshold < 2 {
+		threshold = 2
+	}
+	distances := map[string]int{}
+	matches := []string{}
+	for _, candidate := range candidates {
+		if distance := levenshtein(word, candidate); distance <= threshold {
+			distances[candidate] = distance
+			matches = append(matches, candidate)
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if distances[matches[i]] != distances[matches[j]] {
+			return distances[matches[i]] < distances[matches[j]]
+		}
+		return matches[i] < matches[j]
+	})
+	if len(matches) > n {
+		matches = matches[:n]
+	}
+	return matches
+}
+
+// levenshtein computes the edit distance between a and b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(minInt(previous[j]+1, current[j-1]+1), previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
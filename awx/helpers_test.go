@@ -0,0 +1,244 @@
+package awx
+
+import (
+	"encoding/json"
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+	"unicode"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+	"gopkg.in/yaml.v2"
+)
+
+func TestSuppressEquivalentJSONYaml(t *testing.T) {
+	cases := []struct {
+		old, new string
+		expected bool
+	}{
+		{"", "", true},
+		{"", "{}", true},
+		{"---", "", true},
+		{"# nothing yet\n", "{}", true},
+		{`{"a": 1, "b": [true, "x"]}`, "b:\n  - true\n  - x\na: 1\n", true},
+		{`{"a": 1}`, "a: 1.0", true},
+		{"a: 1 # the answer\n", `{"a":1}`, true},
+		{`{"a": {"b": null}}`, "a:\n  b:\n", true},
+		{`{"a": 1}`, `{"a": "1"}`, false},
+		{`{"a": 1}`, `{"a": 1, "b": 2}`, false},
+		{"a: [1, 2]", "a: [2, 1]", false},
+		{"", "a: 1", false},
+		{"a: 1", "a: [", false},
+	}
+	for _, c := range cases {
+		if suppress := suppressEquivalentJSONYaml("variables", c.old, c.new, nil); suppress != c.expected {
+			t.Errorf("suppressEquivalentJSONYaml(%q, %q) = %t, expected %t", c.old, c.new, suppress, c.expected)
+		}
+	}
+}
+
+func TestValidateJSONYaml(t *testing.T) {
+	valid := []string{"", "---\n", "{}", `{"a": [1, 2]}`, "a:\n  b: c\n", "# comment only\n"}
+	for _, v := range valid {
+		if _, errors := validateJSONYaml(v, "variables"); len(errors) > 0 {
+			t.Errorf("validateJSONYaml(%q) returned %v, expected no error", v, errors)
+		}
+	}
+	invalid := []string{"a: [", `{"a": [}`, "[1, 2]", "just a string", "42", "a: b\n\tc: d"}
+	for _, v := range invalid {
+		if _, errors := validateJSONYaml(v, "variables"); len(errors) == 0 {
+			t.Errorf("validateJSONYaml(%q) returned no error", v)
+		}
+	}
+}
+
+// TestJSONYamlRoundTrip checks a document encoded back as JSON or YAML is parsed to the same structure.
+func TestJSONYamlRoundTrip(t *testing.T) {
+	documents := []string{
+		"",
+		"{}",
+		`{"a": 1, "b": [true, null, "x"], "c": {"d": 1.5}}`,
+		"a: 1\nb:\n  - true\n  - ~\n  - x\nc:\n  d: 1.5\n",
+		"ansible_host: 10.0.0.1\nansible_port: 22\ntags: [web, 'yes', \"no\"]\n",
+		"1: one\n2.5: two\ntrue: three\n",
+		"motd: \"caf\u00e9 \\u2615\"\nempty: ''\nlines: |\n  first\n  second\n",
+		"base: &base\n  user: admin\nweb:\n  <<: *base\n  port: 80\n",
+		`{"big": 12345678901234, "negative": -3, "exponent": 1e3, "nested": [[1, [2]], {"a": {}}]}`,
+		"version: '1.10'\noctal: 0o14\nflag: off\n",
+	}
+	for _, document := range documents {
+		parsed, err := parseJSONYaml(document)
+		if err != nil {
+			t.Fatalf("Cannot parse %q: %s", document, err)
+		}
+		encoded, err := json.Marshal(parsed)
+		if err != nil {
+			t.Fatalf("Cannot encode %v as JSON: %s", parsed, err)
+		}
+		if !suppressEquivalentJSONYaml("variables", string(encoded), document, nil) {
+			t.Errorf("JSON %s not equivalent to %q", encoded, document)
+		}
+		again, err := parseJSONYaml(string(encoded))
+		if err != nil || !reflect.DeepEqual(parsed, again) {
+			t.Errorf("JSON round-trip of %q: %v != %v (%v)", document, again, parsed, err)
+		}
+
+		encoded, err = yaml.Marshal(parsed)
+		if err != nil {
+			t.Fatalf("Cannot encode %v as YAML: %s", parsed, err)
+		}
+		again, err = parseJSONYaml(string(encoded))
+		if err != nil || !reflect.DeepEqual(parsed, again) {
+			t.Errorf("YAML round-trip of %q through %q: %v != %v (%v)", document, encoded, again, parsed, err)
+		}
+	}
+}
+
+// TestJSONYamlProperty checks random variables encoded as JSON and as YAML are parsed to the same structure.
+func TestJSONYamlProperty(t *testing.T) {
+	property := func(v variablesValue) bool {
+		encodedJSON, err := json.Marshal(v.value)
+		if err != nil {
+			t.Logf("Cannot encode %v as JSON: %s", v.value, err)
+			return false
+		}
+		encodedYAML, err := yaml.Marshal(v.value)
+		if err != nil {
+			t.Logf("Cannot encode %v as YAML: %s", v.value, err)
+			return false
+		}
+		fromJSON, errJSON := parseJSONYaml(string(encodedJSON))
+		fromYAML, errYAML := parseJSONYaml(string(encodedYAML))
+		if errJSON != nil || errYAML != nil || !reflect.DeepEqual(fromJSON, v.value) || !reflect.DeepEqual(fromYAML, v.value) {
+			t.Logf("JSON %s and YAML %q parsed to %v (%v) and %v (%v)", encodedJSON, encodedYAML, fromJSON, errJSON, fromYAML, errYAML)
+			return false
+		}
+		return suppressEquivalentJSONYaml("variables", string(encodedJSON), string(encodedYAML), nil)
+	}
+	if err := quick.Check(property, &quick.Config{MaxCount: 500}); err != nil {
+		t.Error(err)
+	}
+}
+
+// variablesValue is a random variables mapping, as parsed by parseJSONYaml.
+type variablesValue struct {
+	value map[string]interface{}
+}
+
+func (variablesValue) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(variablesValue{randomVariablesMap(r, 3)})
+}
+
+func randomVariablesMap(r *rand.Rand, depth int) map[string]interface{} {
+	m := map[string]interface{}{}
+	for i := r.Intn(5); i > 0; i-- {
+		// yaml.v2 writes a << key as is, a merge key once parsed back, it is not a valid variable name anyway
+		if key := randomVariablesString(r); key != "<<" {
+			m[key] = randomVariablesItem(r, depth-1)
+		}
+	}
+	return m
+}
+
+func randomVariablesItem(r *rand.Rand, depth int) interface{} {
+	kinds := 5
+	if depth > 0 {
+		kinds = 7
+	}
+	switch r.Intn(kinds) {
+	case 0:
+		return nil
+	case 1:
+		return r.Intn(2) == 0
+	case 2:
+		return float64(r.Int63n(1<<53) - 1<<52)
+	case 3:
+		return r.NormFloat64() * 1e6
+	case 4:
+		return randomVariablesString(r)
+	case 5:
+		l := []interface{}{}
+		for i := r.Intn(4); i > 0; i-- {
+			l = append(l, randomVariablesItem(r, depth-1))
+		}
+		return l
+	default:
+		return randomVariablesMap(r, depth)
+	}
+}
+
+// randomVariablesString mixes the scalars YAML would resolve to another type with random printable runes.
+func randomVariablesString(r *rand.Rand) string {
+	tricky := []string{"", "yes", "off", "~", "null", "1.10", "0o14", "1e3", "<<", "- x", "a: b", "#", "'", "\"", "\n"}
+	if r.Intn(3) == 0 {
+		return tricky[r.Intn(len(tricky))]
+	}
+	runes, n := []rune{}, r.Intn(12)
+	for len(runes) < n {
+		if c := rune(r.Intn(0x2FFF)); unicode.IsPrint(c) {
+			runes = append(runes, c)
+		}
+	}
+	return string(runes)
+}
+
+func TestCloseMatches(t *testing.T) {
+	playbooks := []string{"hello_world.yml", "site.yml", "deploy.yml", "setup.yml"}
+	cases := []struct {
+		word     string
+		expected []string
+	}{
+		{"hello_wrld.yml", []string{"hello_world.yml"}},
+		{"sites.yml", []string{"site.yml", "setup.yml"}},
+		{"deploy.yaml", []string{"deploy.yml"}},
+		{"upgrade.yml", []string{}},
+	}
+	for _, c := range cases {
+		if matches := closeMatches(c.word, playbooks, 3); !reflect.DeepEqual(matches, c.expected) {
+			t.Errorf("closeMatches(%q) = %v, expected %v", c.word, matches, c.expected)
+		}
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	cases := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"site.yml", "", 8},
+		{"kitten", "sitting", 3},
+		{"site.yml", "site.yml", 0},
+	}
+	for _, c := range cases {
+		if distance := levenshtein(c.a, c.b); distance != c.expected {
+			t.Errorf("levenshtein(%q, %q) = %d, expected %d", c.a, c.b, distance, c.expected)
+		}
+	}
+}
+
+func TestRelatedIDsManaged(t *testing.T) {
+	resource := resourceJobTemplateObject()
+	unset := schema.TestResourceDataRaw(t, resource.Schema, map[string]interface{}{"name": "alpha"})
+	if relatedIDsManaged(unset, "label_ids") {
+		t.Errorf("Expected unset label_ids not to be managed")
+	}
+	set := schema.TestResourceDataRaw(t, resource.Schema, map[string]interface{}{"name": "alpha", "label_ids": []interface{}{1}})
+	if !relatedIDsManaged(set, "label_ids") {
+		t.Errorf("Expected label_ids to be managed")
+	}
+
+	// Emptied in the configuration, the list stays tracked in the state
+	emptied, err := schema.InternalMap(resource.Schema).Data(&terraform.InstanceState{
+		ID:         "1",
+		Attributes: map[string]string{"name": "alpha", "label_ids.#": "0"},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !relatedIDsManaged(emptied, "label_ids") {
+		t.Errorf("Expected emptied label_ids to be managed")
+	}
+}
@@ -0,0 +1,451 @@
+package awx
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// hostFilterNode is a node of a parsed smart inventory host_filter: a boolean operator (and, or, not) or a
+// field=value term.
+type hostFilterNode struct {
+	Op       string
+	Children []*hostFilterNode
+	Field    string
+	Value    string
+}
+
+func (n *hostFilterNode) String() string {
+	switch n.Op {
+	case "not":
+		return "not " + n.Children[0].String()
+	case "and", "or":
+		parts := make([]string, 0, len(n.Children))
+		for _, child := range n.Children {
+			parts = append(parts, child.String())
+		}
+		return "(" + strings.Join(parts, " "+n.Op+" ") + ")"
+	default:
+		value := n.Value
+		if value == "" || strings.ContainsAny(value, " \t()\"'") {
+			value = strconv.Quote(value)
+		}
+		return n.Field + "=" + value
+	}
+}
+
+type hostFilterToken struct {
+	Text  string
+	Pos   int
+	Value string
+}
+
+// hostFilterParser is a recursive descent parser of the host_filter DSL of AWX:
+//
+//	expr := and ("or" and)*
+//	and  := not ("and" not)*
+//	not  := "not" not | "(" expr ")" | field "=" value
+type hostFilterParser struct {
+	tokens []hostFilterToken
+	next   int
+}
+
+// parseHostFilter parses and checks a smart inventory host_filter, e.g. name__icontains=web and not groups__name=db.
+func parseHostFilter(filter string) (*hostFilterNode, error) {
+	tokens, err := tokenizeHostFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("Filter is empty")
+	}
+	p := &hostFilterParser{tokens: tokens}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if token := p.peek(); token != nil {
+		if token.Text == ")" {
+			return nil, fmt.Errorf("Unbalanced parentheses, unexpected ) at position %d", token.Pos)
+		}
+		return nil, fmt.Errorf("Expected and, or or the end of the filter at position %d, got %s", token.Pos, token.Text)
+	}
+	return node, nil
+}
+
+func tokenizeHostFilter(filter string) ([]hostFilterToken, error) {
+	tokens := []hostFilterToken{}
+	runes := []rune(filter)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case r == ' ' || r == '\t' || r == '\n':
+			i++
+		case r == '(' || r == ')':
+			tokens = append(tokens, hostFilterToken{Text: string(r), Pos: i + 1})
+			i++
+		default:
+			start := i
+			var text, value strings.Builder
+			for i < len(runes) && !strings.ContainsRune(" \t\n()", runes[i]) {
+				if runes[i] == '"' || runes[i] == '\'' {
+					quote, open := runes[i], i
+					text.WriteRune(quote)
+					for i++; i < len(runes) && runes[i] != quote; i++ {
+						text.WriteRune(runes[i])
+						value.WriteRune(runes[i])
+					}
+					if i == len(runes) {
+						return nil, fmt.Errorf("Unterminated quote at position %d", open+1)
+					}
+					text.WriteRune(quote)
+					i++
+					continue
+				}
+				text.WriteRune(runes[i])
+				value.WriteRune(runes[i])
+				i++
+			}
+			tokens = append(tokens, hostFilterToken{Text: text.String(), Pos: start + 1, Value: value.String()})
+		}
+	}
+	return tokens, nil
+}
+
+func (p *hostFilterParser) peek() *hostFilterToken {
+	if p.next < len(p.tokens) {
+		return &p.tokens[p.next]
+	}
+	return nil
+}
+
+func (p *hostFilterParser) keyword(word string) bool {
+	if token := p.peek(); token != nil && strings.EqualFold(token.Text, word) {
+		p.next++
+		return true
+	}
+	return false
+}
+
+func (p *hostFilterParser) parseOr() (*hostFilterNode, error) {
+	return p.parseBinary("or", p.parseAnd)
+}
+
+func (p *hostFilterParser) parseAnd() (*hostFilterNode, error) {
+	return p.parseBinary("and", p.parseNot)
+}
+
+func (p *hostFilterParser) parseBinary(op string, operand func() (*hostFilterNode, error)) (*hostFilterNode, error) {
+	node, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	children := []*hostFilterNode{node}
+	for p.keyword(op) {
+		if node, err = operand(); err != nil {
+			return nil, err
+		}
+		children = append(children, node)
+	}
+	if len(children) == 1 {
+		return children[0], nil
+	}
+	return &hostFilterNode{Op: op, Children: children}, nil
+}
+
+func (p *hostFilterParser) parseNot() (*hostFilterNode, error) {
+	if p.keyword("not") {
+		node, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &hostFilterNode{Op: "not", Children: []*hostFilterNode{node}}, nil
+	}
+
+	token := p.peek()
+	if token == nil {
+		return nil, fmt.Errorf("Unexpected end of the filter, expected a field=value term")
+	}
+	p.next++
+	if token.Text == "(" {
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.peek(); closing == nil || closing.Text != ")" {
+			return nil, fmt.Errorf("Unbalanced parentheses, missing ) for ( at position %d", token.Pos)
+		}
+		p.next++
+		return node, nil
+	}
+	return parseHostFilterTerm(token)
+}
+
+func parseHostFilterTerm(token *hostFilterToken) (*hostFilterNode, error) {
+	if token.Text == ")" {
+		return nil, fmt.Errorf("Unbalanced parentheses, unexpected ) at position %d", token.Pos)
+	}
+	equal := strings.Index(token.Text, "=")
+	if equal <= 0 || strings.ContainsAny(token.Text[:equal], "\"'") {
+		return nil, fmt.Errorf("Expected a field=value term at position %d, got %s", token.Pos, token.Text)
+	}
+	field, raw := token.Text[:equal], token.Text[equal+1:]
+	if strings.HasPrefix(raw, "=") || strings.HasSuffix(field, "!") || strings.HasSuffix(field, "<") || strings.HasSuffix(field, ">") {
+		return nil, fmt.Errorf("Invalid operator in %s at position %d, use = with a lookup (e.g. __gt=, __iexact=)", token.Text, token.Pos)
+	}
+	value := strings.TrimPrefix(token.Value, field+"=")
+	if err := checkHostFilterField(field, value); err != nil {
+		return nil, fmt.Errorf("Term %s at position %d: %s", token.Text, token.Pos, err)
+	}
+	return &hostFilterNode{Op: "term", Field: field, Value: value}, nil
+}
+
+type hostFilterModel struct {
+	Name      string
+	Integers  []string
+	Booleans  []string
+	Strings   []string
+	Relations map[string]string
+}
+
+// hostFilterModels are the fields and relations (forward and reverse) of the models a host_filter may look up, as
+// defined by AWX. Dates are checked as strings.
+var hostFilterModels = map[string]*hostFilterModel{
+	"host": {
+		Name:     "hosts",
+		Integers: []string{"id"},
+		Booleans: []string{"enabled", "has_active_failures", "has_inventory_sources"},
+		Strings: []string{"name", "description", "instance_id", "insights_system_id", "variables", "ansible_facts_modified",
+			"created", "modified"},
+		Relations: map[string]string{
+			"groups":                "group",
+			"inventory":             "inventory",
+			"inventory_sources":     "inventory_source",
+			"smart_inventories":     "inventory",
+			"last_job":              "job",
+			"last_job_host_summary": "job_host_summary",
+			"job_host_summaries":    "job_host_summary",
+			"created_by":            "user",
+			"modified_by":           "user",
+		},
+	},
+	"group": {
+		Name:     "groups",
+		Integers: []string{"id"},
+		Strings:  []string{"name", "description", "variables", "created", "modified"},
+		Relations: map[string]string{
+			"inventory":         "inventory",
+			"parents":           "group",
+			"children":          "group",
+			"hosts":             "host",
+			"inventory_sources": "inventory_source",
+			"created_by":        "user",
+			"modified_by":       "user",
+		},
+	},
+	"inventory": {
+		Name: "inventories",
+		Integers: []string{"id", "total_hosts", "hosts_with_active_failures", "total_groups", "total_inventory_sources",
+			"inventory_sources_with_failures"},
+		Booleans: []string{"has_active_failures", "has_inventory_sources", "pending_deletion"},
+		Strings:  []string{"name", "description", "kind", "host_filter", "variables", "created", "modified"},
+		Relations: map[string]string{
+			"organization":      "organization",
+			"groups":            "group",
+			"hosts":             "host",
+			"inventory_sources": "inventory_source",
+			"created_by":        "user",
+			"modified_by":       "user",
+		},
+	},
+	"inventory_source": {
+		Name:     "inventory sources",
+		Integers: []string{"id", "update_cache_timeout", "timeout", "verbosity"},
+		Booleans: []string{"overwrite", "overwrite_vars", "update_on_launch", "last_job_failed"},
+		Strings: []string{"name", "description", "source", "source_path", "source_vars", "enabled_var", "enabled_value",
+			"host_filter", "limit", "status", "last_job_run", "next_job_run", "created", "modified"},
+		Relations: map[string]string{
+			"inventory": "inventory",
+			"groups":    "group",
+			"hosts":     "host",
+		},
+	},
+	"job": {
+		Name:     "jobs",
+		Integers: []string{"id", "forks", "verbosity"},
+		Booleans: []string{"failed"},
+		Strings: []string{"name", "description", "status", "job_type", "launch_type", "playbook", "limit", "scm_branch",
+			"scm_revision", "started", "finished", "elapsed", "created", "modified"},
+		Relations: map[string]string{
+			"inventory":    "inventory",
+			"job_template": "job_template",
+			"project":      "project",
+		},
+	},
+	"job_host_summary": {
+		Name:     "job host summaries",
+		Integers: []string{"id", "changed", "dark", "failures", "ignored", "ok", "processed", "rescued", "skipped"},
+		Booleans: []string{"failed"},
+		Strings:  []string{"host_name", "created", "modified"},
+		Relations: map[string]string{
+			"host": "host",
+			"job":  "job",
+		},
+	},
+	"job_template": {
+		Name:     "job templates",
+		Integers: []string{"id"},
+		Strings:  []string{"name", "description", "playbook", "status", "created", "modified"},
+	},
+	"project": {
+		Name:     "projects",
+		Integers: []string{"id"},
+		Strings:  []string{"name", "description", "scm_type", "scm_url", "scm_branch", "status", "created", "modified"},
+	},
+	"organization": {
+		Name:     "organizations",
+		Integers: []string{"id", "max_hosts"},
+		Strings:  []string{"name", "description", "created", "modified"},
+	},
+	"user": {
+		Name:     "users",
+		Integers: []string{"id"},
+		Booleans: []string{"is_superuser"},
+		Strings:  []string{"username", "first_name", "last_name", "email"},
+	},
+}
+
+var hostFilterLookups = []string{
+	"exact", "iexact", "contains", "icontains", "startswith", "istartswith", "endswith", "iendswith",
+	"regex", "iregex", "gt", "gte", "lt", "lte", "in", "isnull",
+}
+
+// checkHostFilterField checks the path of a field (e.g. groups__name__icontains) and its value.
+func checkHostFilterField(field, value string) error {
+	parts := strings.Split(field, "__")
+	if parts[0] == "ansible_facts" {
+		return checkHostFilterFacts(field, parts[1:])
+	}
+
+	model := hostFilterModels["host"]
+	kind := ""
+	for i, part := range parts {
+		if part == "" {
+			return fmt.Errorf("empty part in field %s", field)
+		}
+		if kind != "" {
+			// Only a lookup may follow a field
+			if i != len(parts)-1 || !stringInSlice(part, hostFilterLookups) {
+				return unknownHostFilterName(part, "lookup", hostFilterLookups)
+			}
+			return checkHostFilterValue(field, kind, part, value)
+		}
+		if related, ok := model.Relations[part]; ok {
+			model = hostFilterModels[related]
+			if i == len(parts)-1 {
+				// A relation alone is looked up by ID
+				return checkHostFilterValue(field, "integer", "exact", value)
+			}
+			continue
+		}
+		switch {
+		case stringInSlice(part, model.Integers):
+			kind = "integer"
+		case stringInSlice(part, model.Booleans):
+			kind = "boolean"
+		case stringInSlice(part, model.Strings):
+			kind = "string"
+		case i > 0 && stringInSlice(part, hostFilterLookups) && i == len(parts)-1:
+			// A lookup on a relation (e.g. groups__in=1,2) applies to its ID
+			return checkHostFilterValue(field, "integer", part, value)
+		default:
+			names := append(append(append([]string{}, model.Integers...), model.Booleans...), model.Strings...)
+			for relation := range model.Relations {
+				names = append(names, relation)
+			}
+			if model == hostFilterModels["host"] {
+				names = append(names, "ansible_facts")
+			}
+			sort.Strings(names)
+			return unknownHostFilterName(part, "field of "+model.Name, names)
+		}
+	}
+	return checkHostFilterValue(field, kind, "exact", value)
+}
+
+// checkHostFilterFacts checks a path in the facts, only exact matches of values or array items ([] suffix) are
+// supported by AWX.
+func checkHostFilterFacts(field string, path []string) error {
+	if len(path) == 0 {
+		return fmt.Errorf("%s requires a path in the facts (e.g. ansible_facts__ansible_distribution)", field)
+	}
+	for i, part := range path {
+		key := strings.TrimSuffix(part, "[]")
+		if key == "" {
+			return fmt.Errorf("empty key in facts path %s", field)
+		}
+		if strings.ContainsAny(key, "[]") {
+			return fmt.Errorf("invalid key %s in facts path %s, [] must end the key", part, field)
+		}
+		if i == len(path)-1 && i > 0 && stringInSlice(part, hostFilterLookups) {
+			return fmt.Errorf("lookup %s is not supported on facts, only exact matches are", part)
+		}
+	}
+	return nil
+}
+
+func checkHostFilterValue(field, kind, lookup, value string) error {
+	switch lookup {
+	case "isnull":
+		if !stringInSlice(strings.ToLower(value), []string{"true", "false"}) {
+			return fmt.Errorf("value must be true or false, got %q", value)
+		}
+		return nil
+	case "in":
+		if value == "" {
+			return fmt.Errorf("value must be a comma separated list")
+		}
+		if kind == "integer" {
+			for _, item := range strings.Split(value, ",") {
+				if _, err := strconv.Atoi(strings.TrimSpace(item)); err != nil {
+					return fmt.Errorf("value must be a comma separated list of integers, got %q", value)
+				}
+			}
+		}
+		return nil
+	case "regex", "iregex", "contains", "icontains", "startswith", "istartswith", "endswith", "iendswith":
+		return nil
+	}
+	switch kind {
+	case "integer":
+		if _, err := strconv.Atoi(value); err != nil {
+			return fmt.Errorf("value must be an integer, got %q", value)
+		}
+	case "boolean":
+		if !stringInSlice(strings.ToLower(value), []string{"true", "false"}) {
+			return fmt.Errorf("value must be true or false, got %q", value)
+		}
+	}
+	return nil
+}
+
+// unknownHostFilterName reports an unknown field or lookup (of a model), with the closest candidates.
+func unknownHostFilterName(name, what string, candidates []string) error {
+	if suggestions := closeMatches(name, candidates, 3); len(suggestions) > 0 {
+		return fmt.Errorf("unknown %s %s, did you mean: %s?", what, name, strings.Join(suggestions, ", "))
+	}
+	return fmt.Errorf("unknown %s %s", what, name)
+}
+
+// validateHostFilter is the ValidateFunc of host_filter, an empty filter is valid.
+func validateHostFilter(v interface{}, k string) (ws []string, errors []error) {
+	filter := v.(string)
+	if strings.TrimSpace(filter) == "" {
+		return
+	}
+	if _, err := parseHostFilter(filter); err != nil {
+		errors = append(errors, fmt.Errorf("%s: %s", k, err))
+	}
+	return
+}
@@ -0,0 +1,102 @@
+package awx
+
+import (
+	"testing"
+)
+
+func TestParseHostFilter(t *testing.T) {
+	cases := map[string]string{
+		"name=web1":                                  "name=web1",
+		`name="web 1"`:                               `name="web 1"`,
+		"name='(web)'":                               `name="(web)"`,
+		"name__icontains=web and enabled=true":       "(name__icontains=web and enabled=true)",
+		"a_name=x":                                   "",
+		"name=a or name=b and not name=c":            "(name=a or (name=b and not name=c))",
+		"(name=a or name=b) AND not (name=c)":        "((name=a or name=b) and not name=c)",
+		"not not enabled=False":                      "not not enabled=False",
+		"groups__name=web":                           "groups__name=web",
+		"groups=1":                                   "groups=1",
+		"groups__in=1,2":                             "groups__in=1,2",
+		"groups__name__iexact=Web":                   "groups__name__iexact=Web",
+		"inventory__organization__name=Default":      "inventory__organization__name=Default",
+		"id__gte=10":                                 "id__gte=10",
+		"instance_id__isnull=true":                   "instance_id__isnull=true",
+		"ansible_facts__ansible_distribution=Ubuntu": "ansible_facts__ansible_distribution=Ubuntu",
+		"ansible_facts__ansible_lo__ipv4__address=127.0.0.1":         "ansible_facts__ansible_lo__ipv4__address=127.0.0.1",
+		`ansible_facts__ansible_processor[]="GenuineIntel"`:          "ansible_facts__ansible_processor[]=GenuineIntel",
+		"name__regex=^web[0-9]+$ or description__contains=front-end": "(name__regex=^web[0-9]+$ or description__contains=front-end)",
+		"groups__parents__name=web":                                  "groups__parents__name=web",
+		"groups__children__hosts__name__startswith=db":               "groups__children__hosts__name__startswith=db",
+		"inventory_sources__source=ec2":                              "inventory_sources__source=ec2",
+		"last_job__status=failed":                                    "last_job__status=failed",
+		"last_job=42":                                                "last_job=42",
+		"last_job__isnull=true":                                      "last_job__isnull=true",
+		"last_job_host_summary__failed=true":                         "last_job_host_summary__failed=true",
+		"last_job_host_summary__failures__gt=0":                      "last_job_host_summary__failures__gt=0",
+		"ansible_facts_modified__isnull=false":                       "ansible_facts_modified__isnull=false",
+		"ansible_facts_modified__gt=2024-01-01":                      "ansible_facts_modified__gt=2024-01-01",
+		"inventory__organization__max_hosts__lte=100":                "inventory__organization__max_hosts__lte=100",
+		"created_by__username=admin":                                 "created_by__username=admin",
+	}
+	for filter, expected := range cases {
+		node, err := parseHostFilter(filter)
+		if expected == "" {
+			if err == nil {
+				t.Errorf("parseHostFilter(%q) = %s, expected an error", filter, node)
+			}
+		} else if err != nil {
+			t.Errorf("parseHostFilter(%q) returned an unexpected error %v", filter, err)
+		} else if actual := node.String(); actual != expected {
+			t.Errorf("parseHostFilter(%q) = %s, expected %s", filter, actual, expected)
+		}
+	}
+}
+
+func TestParseHostFilterErrors(t *testing.T) {
+	cases := map[string]string{
+		"":                                "Filter is empty",
+		"(name=a or name=b":               "Unbalanced parentheses, missing ) for ( at position 1",
+		"name=a)":                         "Unbalanced parentheses, unexpected ) at position 7",
+		"()":                              "Unbalanced parentheses, unexpected ) at position 2",
+		"name=a or":                       "Unexpected end of the filter, expected a field=value term",
+		"name=a name=b":                   "Expected and, or or the end of the filter at position 8, got name=b",
+		"and name=a":                      "Expected a field=value term at position 1, got and",
+		"web1":                            "Expected a field=value term at position 1, got web1",
+		`name="web`:                       "Unterminated quote at position 6",
+		"name==web":                       "Invalid operator in name==web at position 1, use = with a lookup (e.g. __gt=, __iexact=)",
+		"name!=web":                       "Invalid operator in name!=web at position 1, use = with a lookup (e.g. __gt=, __iexact=)",
+		"nme=web":                         "Term nme=web at position 1: unknown field of hosts nme, did you mean: name?",
+		"groups__nam=web":                 "Term groups__nam=web at position 1: unknown field of groups nam, did you mean: name?",
+		"name__icontain=web":              "Term name__icontain=web at position 1: unknown lookup icontain, did you mean: icontains, contains?",
+		"name__icontains__exact=web":      "Term name__icontains__exact=web at position 1: unknown lookup icontains, did you mean: icontains, contains?",
+		"name____icontains=web":           "Term name____icontains=web at position 1: empty part in field name____icontains",
+		"id=web":                          `Term id=web at position 1: value must be an integer, got "web"`,
+		"enabled=yes":                     `Term enabled=yes at position 1: value must be true or false, got "yes"`,
+		"groups__in=web,db":               `Term groups__in=web,db at position 1: value must be a comma separated list of integers, got "web,db"`,
+		"name__isnull=maybe":              `Term name__isnull=maybe at position 1: value must be true or false, got "maybe"`,
+		"ansible_facts=Ubuntu":            "Term ansible_facts=Ubuntu at position 1: ansible_facts requires a path in the facts (e.g. ansible_facts__ansible_distribution)",
+		"ansible_facts__os__icontains=ub": "Term ansible_facts__os__icontains=ub at position 1: lookup icontains is not supported on facts, only exact matches are",
+		"ansible_facts____os=ubuntu":      "Term ansible_facts____os=ubuntu at position 1: empty key in facts path ansible_facts____os",
+		"ansible_facts__ip[]x=1":          "Term ansible_facts__ip[]x=1 at position 1: invalid key ip[]x in facts path ansible_facts__ip[]x, [] must end the key",
+		"enabled=true and (name=a or x)":  "Expected a field=value term at position 29, got x",
+		"last_job__failed=maybe":          `Term last_job__failed=maybe at position 1: value must be true or false, got "maybe"`,
+		"groups__parent__name=web":        "Term groups__parent__name=web at position 1: unknown field of groups parent, did you mean: parents?",
+	}
+	for filter, expected := range cases {
+		if _, err := parseHostFilter(filter); err == nil || err.Error() != expected {
+			t.Errorf("parseHostFilter(%q) returned error %v, expected %q", filter, err, expected)
+		}
+	}
+}
+
+func TestValidateHostFilter(t *testing.T) {
+	if _, errs := validateHostFilter("", "host_filter"); len(errs) != 0 {
+		t.Errorf("Expected an empty filter to be valid, got %v", errs)
+	}
+	if _, errs := validateHostFilter("name=", "host_filter"); len(errs) != 0 {
+		t.Errorf("Expected an empty value to be valid, got %v", errs)
+	}
+	if _, errs := validateHostFilter("name=a)", "host_filter"); len(errs) != 1 || errs[0].Error() != "host_filter: Unbalanced parentheses, unexpected ) at position 7" {
+		t.Errorf("Unexpected errors %v", errs)
+	}
+}
@@ -0,0 +1,698 @@
+package awx
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+var inventoryFileFormats = []string{"ini", "yaml"}
+
+// Upper bound of hosts of an inventory file, host ranges are expanded at every plan
+const inventoryFileMaxHosts = 10000
+
+// parseInventoryFile parses an Ansible inventory in the given format (ini or yaml).
+func parseInventoryFile(format, content string) (*inventoryLayout, error) {
+	var layout *inventoryLayout
+	var err error
+	switch format {
+	case "ini":
+		layout, err = parseInventoryINI(content)
+	case "yaml":
+		layout, err = parseInventoryYAML(content)
+	default:
+		return nil, fmt.Errorf("Unsupported inventory format %q, expected one of: %s", format, strings.Join(inventoryFileFormats, ", "))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(layout.Hosts) > inventoryFileMaxHosts {
+		return nil, fmt.Errorf("Inventory has %d hosts, more than the maximum of %d", len(layout.Hosts), inventoryFileMaxHosts)
+	}
+	layout.normalize()
+	if err := layout.validate(); err != nil {
+		return nil, err
+	}
+	return layout, nil
+}
+
+var iniSectionRegexp = regexp.MustCompile(`^\[([^\]:\s]+)(?::(\w+))?\]\s*(?:#.*)?$`)
+
+// parseInventoryINI parses an inventory in the Ansible INI format: host lines with inline variables, [group],
+// [group:vars] and [group:children] sections. The variables of [all:vars] are the variables of the inventory.
+func parseInventoryINI(content string) (*inventoryLayout, error) {
+	layout := newInventoryLayout()
+	group, kind := "ungrouped", "hosts"
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for lineno := 1; scanner.Scan(); lineno++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			match := iniSectionRegexp.FindStringSubmatch(line)
+			if match == nil {
+				return nil, fmt.Errorf("Line %d: invalid section %q", lineno, line)
+			}
+			group, kind = match[1], match[2]
+			switch kind {
+			case "":
+				kind = "hosts"
+			case "vars", "children":
+			default:
+				return nil, fmt.Errorf("Line %d: invalid section type %q, expected vars or children", lineno, kind)
+			}
+			if !isImplicitGroup(group) {
+				layout.group(group)
+			}
+			continue
+		}
+
+		switch kind {
+		case "hosts":
+			tokens, err := splitINILine(line)
+			if err != nil {
+				return nil, fmt.Errorf("Line %d: %s", lineno, err)
+			}
+			variables := map[string]interface{}{}
+			for _, token := range tokens[1:] {
+				parts := strings.SplitN(token, "=", 2)
+				if len(parts) != 2 || parts[0] == "" {
+					return nil, fmt.Errorf("Line %d: expected key=value host variable, got %q", lineno, token)
+				}
+				variables[parts[0]] = parseINIValue(parts[1])
+			}
+			pattern, port, err := splitHostPort(tokens[0])
+			if err != nil {
+				return nil, fmt.Errorf("Line %d: %s", lineno, err)
+			}
+			if port != 0 {
+				variables["ansible_port"] = float64(port)
+			}
+			names, err := expandHostPattern(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("Line %d: %s", lineno, err)
+			}
+			for _, name := range names {
+				host := layout.host(name)
+				for k, v := range variables {
+					host.Variables[k] = v
+				}
+				if !isImplicitGroup(group) {
+					host.Groups = append(host.Groups, group)
+				}
+			}
+		case "vars":
+			parts := strings.SplitN(line, "=", 2)
+			key := strings.TrimSpace(parts[0])
+			if len(parts) != 2 || key == "" {
+				return nil, fmt.Errorf("Line %d: expected key=value group variable, got %q", lineno, line)
+			}
+			value := parseINIValue(strings.TrimSpace(parts[1]))
+			switch group {
+			case "all":
+				if layout.Variables == nil {
+					layout.Variables = map[string]interface{}{}
+				}
+				layout.Variables[key] = value
+			case "ungrouped":
+				return nil, fmt.Errorf("Line %d: variables of group ungrouped are not supported", lineno)
+			default:
+				layout.group(group).Variables[key] = value
+			}
+		case "children":
+			tokens, err := splitINILine(line)
+			if err != nil {
+				return nil, fmt.Errorf("Line %d: %s", lineno, err)
+			}
+			if len(tokens) != 1 {
+				return nil, fmt.Errorf("Line %d: expected a group name, got %q", lineno, line)
+			}
+			child := tokens[0]
+			if isImplicitGroup(child) {
+				return nil, fmt.Errorf("Line %d: group %s cannot be a child group", lineno, child)
+			}
+			layout.group(child)
+			if group != "all" {
+				layout.group(group).Children = append(layout.group(group).Children, child)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return layout, nil
+}
+
+// splitINILine splits a host line like Python's shlex (POSIX mode with comments) as Ansible does: quotes are removed,
+// a backslash escapes the next character and an unquoted # starts a comment.
+func splitINILine(line string) ([]string, error) {
+	tokens := []string{}
+	var token strings.Builder
+	var quote rune
+	inToken, escaped := false, false
+	for _, r := range line {
+		switch {
+		case escaped:
+			token.WriteRune(r)
+			escaped = false
+		case quote == '\'':
+			if r == '\'' {
+				quote = 0
+			} else {
+				token.WriteRune(r)
+			}
+		case quote == '"':
+			if r == '"' {
+				quote = 0
+			} else if r == '\\' {
+				escaped = true
+			} else {
+				token.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inToken = true
+		case r == '\\':
+			escaped = true
+			inToken = true
+		case r == ' ' || r == '\t':
+			if inToken {
+				tokens = append(tokens, token.String())
+				token.Reset()
+				inToken = false
+			}
+		case r == '#':
+			if inToken {
+				tokens = append(tokens, token.String())
+			}
+			return checkINITokens(line, tokens)
+		default:
+			inToken = true
+			token.WriteRune(r)
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, fmt.Errorf("unterminated quote in %q", line)
+	}
+	if inToken {
+		tokens = append(tokens, token.String())
+	}
+	return checkINITokens(line, tokens)
+}
+
+func checkINITokens(line string, tokens []string) ([]string, error) {
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("no host in %q", line)
+	}
+	return tokens, nil
+}
+
+var iniNumberRegexp = regexp.MustCompile(`^[-+]?(\d+\.?\d*|\.\d+)([eE][-+]?\d+)?$`)
+
+// parseINIValue converts a value like Ansible does, a Python literal (string, number, boolean, None, list or dict)
+// or the value as a string otherwise.
+func parseINIValue(raw string) interface{} {
+	if len(raw) >= 2 && raw[0] == '"' && raw[len(raw)-1] == '"' {
+		if value, err := strconv.Unquote(raw); err == nil {
+			return value
+		}
+	}
+	if len(raw) >= 2 && raw[0] == '\'' && raw[len(raw)-1] == '\'' {
+		value := raw[1 : len(raw)-1]
+		if !strings.Contains(strings.Replace(value, `\'`, "", -1), "'") {
+			return strings.Replace(strings.Replace(value, `\'`, "'", -1), `\\`, `\`, -1)
+		}
+	}
+	switch raw {
+	case "True":
+		return true
+	case "False":
+		return false
+	case "None":
+		return nil
+	}
+	if iniNumberRegexp.MatchString(raw) {
+		if f, err := strconv.ParseFloat(raw, 64); err == nil {
+			return f
+		}
+	}
+	if strings.HasPrefix(raw, "[") || strings.HasPrefix(raw, "{") {
+		var value interface{}
+		if err := yaml.Unmarshal([]byte(raw), &value); err == nil {
+			return normalizeParsed(value)
+		}
+	}
+	return raw
+}
+
+// splitHostPort splits host:port, the colons of ranges and IPv6 addresses are not separators.
+func splitHostPort(pattern string) (string, int, error) {
+	depth, colon, colons := 0, -1, 0
+	for i, r := range pattern {
+		switch r {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case ':':
+			if depth == 0 {
+				colon = i
+				colons++
+			}
+		}
+	}
+	if colons != 1 {
+		return pattern, 0, nil
+	}
+	port, err := strconv.Atoi(pattern[colon+1:])
+	if err != nil || port <= 0 || port > 65535 {
+		return "", 0, fmt.Errorf("invalid port in %q", pattern)
+	}
+	return pattern[:colon], port, nil
+}
+
+var hostRangeRegexp = regexp.MustCompile(`\[([0-9a-zA-Z]+):([0-9a-zA-Z]+)(?::(\d+))?\]`)
+
+// expandHostPattern expands the ranges of a host pattern, e.g. web[01:03] or db-[a:c].example.com.
+func expandHostPattern(pattern string) ([]string, error) {
+	return expandHostPatternLimit(pattern, pattern, inventoryFileMaxHosts)
+}
+
+// expandHostPatternLimit expands the ranges of pattern, refusing before expanding them to go over limit names.
+func expandHostPatternLimit(original, pattern string, limit int) ([]string, error) {
+	match := hostRangeRegexp.FindStringSubmatchIndex(pattern)
+	if match == nil {
+		if strings.ContainsAny(pattern, "[]") && !strings.Contains(pattern, "::") {
+			return nil, fmt.Errorf("invalid host pattern %q", pattern)
+		}
+		if limit < 1 {
+			return nil, fmt.Errorf("host pattern %q expands to more than %d hosts", original, inventoryFileMaxHosts)
+		}
+		return []string{pattern}, nil
+	}
+	prefix, suffix := pattern[:match[0]], pattern[match[1]:]
+	start, end := pattern[match[2]:match[3]], pattern[match[4]:match[5]]
+	step := 1
+	if match[6] >= 0 {
+		step, _ = strconv.Atoi(pattern[match[6]:match[7]])
+		if step <= 0 {
+			return nil, fmt.Errorf("invalid step in host pattern %q", pattern)
+		}
+	}
+
+	var values []string
+	first, errFirst := strconv.Atoi(start)
+	last, errLast := strconv.Atoi(end)
+	switch {
+	case errFirst == nil && errLast == nil:
+		if first > last {
+			return nil, fmt.Errorf("invalid range in host pattern %q, start is greater than end", pattern)
+		}
+		if (last-first)/step >= limit {
+			return nil, fmt.Errorf("host pattern %q expands to more than %d hosts", original, inventoryFileMaxHosts)
+		}
+		format := "%d"
+		if len(start) > 1 && start[0] == '0' {
+			format = fmt.Sprintf("%%0%dd", len(start))
+		}
+		for i := first; i <= last; i += step {
+			values = append(values, fmt.Sprintf(format, i))
+		}
+	case len(start) == 1 && len(end) == 1 && errFirst != nil && errLast != nil:
+		if start[0] > end[0] {
+			return nil, fmt.Errorf("invalid range in host pattern %q, start is greater than end", pattern)
+		}
+		if int(end[0]-start[0])/step >= limit {
+			return nil, fmt.Errorf("host pattern %q expands to more than %d hosts", original, inventoryFileMaxHosts)
+		}
+		for c := int(start[0]); c <= int(end[0]); c += step {
+			values = append(values, string(rune(c)))
+		}
+	default:
+		return nil, fmt.Errorf("invalid range in host pattern %q", pattern)
+	}
+
+	names := []string{}
+	for _, value := range values {
+		expanded, err := expandHostPatternLimit(original, prefix+value+suffix, limit-len(names))
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, expanded...)
+	}
+	return names, nil
+}
+
+// parseInventoryYAML parses an inventory in the Ansible YAML format, groups with hosts, vars and children.
+func parseInventoryYAML(content string) (*inventoryLayout, error) {
+	var document map[string]interface{}
+	if err := yaml.Unmarshal([]byte(content), &document); err != nil {
+		return nil, err
+	}
+	layout := newInventoryLayout()
+	for _, name := range sortedKeys(document) {
+		if err := parseYAMLGroup(layout, name, "", document[name]); err != nil {
+			return nil, err
+		}
+	}
+	return layout, nil
+}
+
+func parseYAMLGroup(layout *inventoryLayout, name, parent string, raw interface{}) error {
+	if name == "ungrouped" && parent != "" && parent != "all" {
+		return fmt.Errorf("Group ungrouped cannot be a child of %s", parent)
+	}
+	if name == "all" && parent != "" {
+		return fmt.Errorf("Group all cannot be a child of %s", parent)
+	}
+	if !isImplicitGroup(name) {
+		layout.group(name)
+		if parent != "" && parent != "all" {
+			layout.group(parent).Children = append(layout.group(parent).Children, name)
+		}
+	}
+	if raw == nil {
+		return nil
+	}
+	section, ok := normalizeParsed(raw).(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("Group %s must be a mapping with hosts, vars and children", name)
+	}
+	for key := range section {
+		if key != "hosts" && key != "vars" && key != "children" {
+			return fmt.Errorf("Group %s has an invalid key %s, expected hosts, vars or children", name, key)
+		}
+	}
+
+	if raw, ok := section["vars"]; ok && raw != nil {
+		variables, ok := raw.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("Variables of group %s must be a mapping", name)
+		}
+		switch name {
+		case "all":
+			if layout.Variables == nil {
+				layout.Variables = map[string]interface{}{}
+			}
+			for k, v := range variables {
+				layout.Variables[k] = v
+			}
+		case "ungrouped":
+			return fmt.Errorf("Variables of group ungrouped are not supported")
+		default:
+			for k, v := range variables {
+				layout.group(name).Variables[k] = v
+			}
+		}
+	}
+
+	if raw, ok := section["hosts"]; ok && raw != nil {
+		hosts, ok := raw.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("Hosts of group %s must be a mapping", name)
+		}
+		for _, pattern := range sortedKeys(hosts) {
+			variables := map[string]interface{}{}
+			if hosts[pattern] != nil {
+				if variables, ok = hosts[pattern].(map[string]interface{}); !ok {
+					return fmt.Errorf("Variables of host %s must be a mapping", pattern)
+				}
+			}
+			names, err := expandHostPattern(pattern)
+			if err != nil {
+				return err
+			}
+			for _, host := range names {
+				h := layout.host(host)
+				for k, v := range variables {
+					h.Variables[k] = v
+				}
+				if !isImplicitGroup(name) {
+					h.Groups = append(h.Groups, name)
+				}
+			}
+		}
+	}
+
+	if raw, ok := section["children"]; ok && raw != nil {
+		children, ok := raw.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("Children of group %s must be a mapping", name)
+		}
+		for _, child := range sortedKeys(children) {
+			if err := parseYAMLGroup(layout, child, name, children[child]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func isImplicitGroup(name string) bool {
+	return name == "all" || name == "ungrouped"
+}
+
+// renderInventoryINI renders a layout in the Ansible INI format, the variables of a host are set where it appears first.
+func renderInventoryINI(layout *inventoryLayout) string {
+	var b strings.Builder
+	rendered := map[string]bool{}
+	hostLine := func(name string) string {
+		if rendered[name] {
+			return name
+		}
+		rendered[name] = true
+		variables := layout.Hosts[name].Variables
+		line := name
+		for _, key := range sortedKeys(variables) {
+			line += " " + key + "=" + encodeINIValue(variables[key], true)
+		}
+		return line
+	}
+
+	for _, name := range sortedHostNames(layout.Hosts) {
+		if len(layout.Hosts[name].Groups) == 0 {
+			fmt.Fprintln(&b, hostLine(name))
+		}
+	}
+	if len(layout.Variables) > 0 {
+		if b.Len() > 0 {
+			fmt.Fprintln(&b)
+		}
+		fmt.Fprintln(&b, "[all:vars]")
+		for _, key := range sortedKeys(layout.Variables) {
+			fmt.Fprintf(&b, "%s=%s\n", key, encodeINIValue(layout.Variables[key], false))
+		}
+	}
+
+	members := map[string][]string{}
+	for _, name := range sortedHostNames(layout.Hosts) {
+		for _, group := range layout.Hosts[name].Groups {
+			members[group] = append(members[group], name)
+		}
+	}
+	for _, name := range sortedGroupNames(layout.Groups) {
+		g := layout.Groups[name]
+		if b.Len() > 0 {
+			fmt.Fprintln(&b)
+		}
+		fmt.Fprintf(&b, "[%s]\n", name)
+		for _, host := range members[name] {
+			fmt.Fprintln(&b, hostLine(host))
+		}
+		if len(g.Variables) > 0 {
+			fmt.Fprintf(&b, "\n[%s:vars]\n", name)
+			for _, key := range sortedKeys(g.Variables) {
+				fmt.Fprintf(&b, "%s=%s\n", key, encodeINIValue(g.Variables[key], false))
+			}
+		}
+		if len(g.Children) > 0 {
+			fmt.Fprintf(&b, "\n[%s:children]\n", name)
+			for _, child := range g.Children {
+				fmt.Fprintln(&b, child)
+			}
+		}
+	}
+	return b.String()
+}
+
+// encodeINIValue encodes a value so that parseINIValue decodes it back, values of host lines are also quoted for
+// splitINILine.
+func encodeINIValue(value interface{}, hostLine bool) string {
+	var literal string
+	switch v := value.(type) {
+	case nil:
+		literal = "None"
+	case bool:
+		literal = "False"
+		if v {
+			literal = "True"
+		}
+	case float64:
+		literal = strconv.FormatFloat(v, 'f', -1, 64)
+	case string:
+		literal = v
+		if parsed, ok := parseINIValue(v).(string); !ok || parsed != v || strings.TrimSpace(v) != v {
+			literal = strconv.Quote(v)
+		}
+	default:
+		b, _ := json.Marshal(v)
+		literal = string(b)
+	}
+	if !hostLine || (literal != "" && !strings.ContainsAny(literal, " \t\"'\\#")) {
+		return literal
+	}
+	if !strings.Contains(literal, "'") {
+		return "'" + literal + "'"
+	}
+	return `"` + strings.Replace(strings.Replace(literal, `\`, `\\`, -1), `"`, `\"`, -1) + `"`
+}
+
+// renderInventoryYAML renders a layout in the Ansible YAML format, every group is declared under all and refers to
+// its children.
+func renderInventoryYAML(layout *inventoryLayout) (string, error) {
+	all := yaml.MapSlice{}
+	if len(layout.Variables) > 0 {
+		all = append(all, yaml.MapItem{Key: "vars", Value: yamlMapping(layout.Variables)})
+	}
+	rendered := map[string]bool{}
+	hostsOf := func(names []string) yaml.MapSlice {
+		hosts := yaml.MapSlice{}
+		for _, name := range names {
+			var variables interface{}
+			if !rendered[name] && len(layout.Hosts[name].Variables) > 0 {
+				variables = yamlMapping(layout.Hosts[name].Variables)
+			}
+			rendered[name] = true
+			hosts = append(hosts, yaml.MapItem{Key: name, Value: variables})
+		}
+		return hosts
+	}
+
+	ungrouped := []string{}
+	members := map[string][]string{}
+	for _, name := range sortedHostNames(layout.Hosts) {
+		if len(layout.Hosts[name].Groups) == 0 {
+			ungrouped = append(ungrouped, name)
+		}
+		for _, group := range layout.Hosts[name].Groups {
+			members[group] = append(members[group], name)
+		}
+	}
+	if len(ungrouped) > 0 {
+		all = append(all, yaml.MapItem{Key: "hosts", Value: hostsOf(ungrouped)})
+	}
+
+	children := yaml.MapSlice{}
+	for _, name := range sortedGroupNames(layout.Groups) {
+		g := layout.Groups[name]
+		group := yaml.MapSlice{}
+		if len(members[name]) > 0 {
+			group = append(group, yaml.MapItem{Key: "hosts", Value: hostsOf(members[name])})
+		}
+		if len(g.Variables) > 0 {
+			group = append(group, yaml.MapItem{Key: "vars", Value: yamlMapping(g.Variables)})
+		}
+		if len(g.Children) > 0 {
+			refs := yaml.MapSlice{}
+			for _, child := range g.Children {
+				refs = append(refs, yaml.MapItem{Key: child, Value: map[string]interface{}{}})
+			}
+			group = append(group, yaml.MapItem{Key: "children", Value: refs})
+		}
+		var value interface{}
+		if len(group) > 0 {
+			value = group
+		}
+		children = append(children, yaml.MapItem{Key: name, Value: value})
+	}
+	if len(children) > 0 {
+		all = append(all, yaml.MapItem{Key: "children", Value: children})
+	}
+
+	b, err := yaml.Marshal(yaml.MapSlice{{Key: "all", Value: all}})
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// yamlMapping sorts the keys of a mapping so the rendering is stable.
+func yamlMapping(m map[string]interface{}) yaml.MapSlice {
+	mapping := yaml.MapSlice{}
+	for _, k := range sortedKeys(m) {
+		mapping = append(mapping, yaml.MapItem{Key: k, Value: m[k]})
+	}
+	return mapping
+}
+
+// renderInventoryJSON renders a layout in the JSON format of Ansible dynamic inventory scripts.
+func renderInventoryJSON(layout *inventoryLayout) (string, error) {
+	all := map[string]interface{}{}
+	document := map[string]interface{}{"all": all}
+	if len(layout.Variables) > 0 {
+		all["vars"] = layout.Variables
+	}
+
+	hasParent := map[string]bool{}
+	for _, g := range layout.Groups {
+		for _, child := range g.Children {
+			hasParent[child] = true
+		}
+	}
+	children := []string{}
+	for _, name := range sortedGroupNames(layout.Groups) {
+		if !hasParent[name] {
+			children = append(children, name)
+		}
+	}
+	if len(children) > 0 {
+		all["children"] = children
+	}
+
+	ungrouped := []string{}
+	members := map[string][]string{}
+	hostvars := map[string]interface{}{}
+	for _, name := range sortedHostNames(layout.Hosts) {
+		h := layout.Hosts[name]
+		if len(h.Groups) == 0 {
+			ungrouped = append(ungrouped, name)
+		}
+		for _, group := range h.Groups {
+			members[group] = append(members[group], name)
+		}
+		hostvars[name] = h.Variables
+	}
+	if len(ungrouped) > 0 {
+		all["hosts"] = ungrouped
+	}
+
+	for name, g := range layout.Groups {
+		group := map[string]interface{}{}
+		if len(members[name]) > 0 {
+			group["hosts"] = members[name]
+		}
+		if len(g.Children) > 0 {
+			group["children"] = g.Children
+		}
+		if len(g.Variables) > 0 {
+			group["vars"] = g.Variables
+		}
+		document[name] = group
+	}
+	document["_meta"] = map[string]interface{}{"hostvars": hostvars}
+
+	b, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(b) + "\n", nil
+}
@@ -0,0 +1,241 @@
+package awx
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testInventoryINI = `
+# Comments and blank lines are ignored
+mail.example.com ansible_user=root
+
+[webservers]
+web[01:03].example.com http_port=80
+web01.example.com ansible_host=10.0.0.1 motd="hello world"
+
+[dbservers]
+db-[a:b].example.com:2222 backup=True  # inline comment
+db-a.example.com ratio=0.5 tags='["a", "b"]' nothing=None
+
+[webservers:vars]
+ntp_server = ntp.example.com
+max_clients=200
+
+[datacenter:children]
+webservers
+dbservers
+
+[datacenter:vars]
+location="Lausanne"
+
+[all:vars]
+env=production
+`
+
+func TestParseInventoryINI(t *testing.T) {
+	layout, err := parseInventoryFile("ini", testInventoryINI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := newInventoryLayout()
+	expected.Variables = map[string]interface{}{"env": "production"}
+	expected.group("webservers").Variables = map[string]interface{}{"ntp_server": "ntp.example.com", "max_clients": float64(200)}
+	expected.group("dbservers")
+	expected.group("datacenter").Variables = map[string]interface{}{"location": "Lausanne"}
+	expected.Groups["datacenter"].Children = []string{"dbservers", "webservers"}
+
+	expected.host("mail.example.com").Variables = map[string]interface{}{"ansible_user": "root"}
+	web01 := expected.host("web01.example.com")
+	web01.Variables = map[string]interface{}{"http_port": float64(80), "ansible_host": "10.0.0.1", "motd": "hello world"}
+	for _, name := range []string{"web01.example.com", "web02.example.com", "web03.example.com"} {
+		h := expected.host(name)
+		h.Variables["http_port"] = float64(80)
+		h.Groups = []string{"webservers"}
+	}
+	for _, name := range []string{"db-a.example.com", "db-b.example.com"} {
+		h := expected.host(name)
+		h.Variables = map[string]interface{}{"ansible_port": float64(2222), "backup": true}
+		h.Groups = []string{"dbservers"}
+	}
+	dba := expected.Hosts["db-a.example.com"]
+	dba.Variables["ratio"] = 0.5
+	dba.Variables["tags"] = []interface{}{"a", "b"}
+	dba.Variables["nothing"] = nil
+
+	if !reflect.DeepEqual(layout, expected) {
+		t.Fatalf("Unexpected layout\n%s\nexpected\n%s", renderInventoryINI(layout), renderInventoryINI(expected))
+	}
+}
+
+func TestParseInventoryINIErrors(t *testing.T) {
+	cases := map[string]string{
+		"[web\nweb01\n":                          `Line 1: invalid section "[web"`,
+		"[web:hosts]\nweb01\n":                   `Line 1: invalid section type "hosts", expected vars or children`,
+		"[web:vars]\nport\n":                     `Line 2: expected key=value group variable, got "port"`,
+		"[a:children]\nb\n[b:children]\na\n":     "Groups hierarchy has a cycle: a -> b -> a",
+		"[web]\nweb[3:1]\n":                      `Line 2: invalid range in host pattern "web[3:1]", start is greater than end`,
+		"[web]\nweb01 motd='hello\n":             `Line 2: unterminated quote in "web01 motd='hello"`,
+		"[web]\nweb01:http\n":                    `Line 2: invalid port in "web01:http"`,
+		"[ungrouped:vars]\na=1\n":                "Line 2: variables of group ungrouped are not supported",
+		"[web:children]\nall\n":                  "Line 2: group all cannot be a child group",
+		"[web]\nweb01 a=1 ; comment\n":           `Line 2: expected key=value host variable, got ";"`,
+		"[web]\nweb01\n[db]\ndb01 a=1 b\n":       `Line 4: expected key=value host variable, got "b"`,
+		"[web]\nweb[01:x]\n":                     `Line 2: invalid range in host pattern "web[01:x]"`,
+		"[web]\nweb[1:4:0]\n":                    `Line 2: invalid step in host pattern "web[1:4:0]"`,
+		"[web]\nweb]\n":                          `Line 2: invalid host pattern "web]"`,
+		"[web]\nweb[0:99999999]\n":               `Line 2: host pattern "web[0:99999999]" expands to more than 10000 hosts`,
+		"[web]\nr[1:100]n[0:99]x[a:b]\n":         `Line 2: host pattern "r[1:100]n[0:99]x[a:b]" expands to more than 10000 hosts`,
+		"[web]\nweb[1:6000]\n[db]\ndb[1:6000]\n": "Inventory has 12000 hosts, more than the maximum of 10000",
+		"[web]\nweb[1:10000]\n":                  "",
+		"[all:children]\nweb\n[web]\nweb01\n":    "",
+		"[web]\n2001:db8::2\n":                   "",
+		"[web]\nweb01 a=b#c\n":                   "",
+		"[web]\nweb01 # comment a=1\n; note\n":   "",
+	}
+	for content, expected := range cases {
+		_, err := parseInventoryFile("ini", content)
+		if expected == "" {
+			if err != nil {
+				t.Errorf("parseInventoryFile(%q) returned an unexpected error %v", content, err)
+			}
+		} else if err == nil || err.Error() != expected {
+			t.Errorf("parseInventoryFile(%q) returned error %v, expected %q", content, err, expected)
+		}
+	}
+}
+
+func TestExpandHostPattern(t *testing.T) {
+	cases := map[string][]string{
+		"web01":             {"web01"},
+		"web[1:3]":          {"web1", "web2", "web3"},
+		"web[08:10].local":  {"web08.local", "web09.local", "web10.local"},
+		"web[0:6:3]":        {"web0", "web3", "web6"},
+		"db-[a:c]":          {"db-a", "db-b", "db-c"},
+		"r[1:2]-n[a:b]":     {"r1-na", "r1-nb", "r2-na", "r2-nb"},
+		"10.0.0.[1:2]":      {"10.0.0.1", "10.0.0.2"},
+		"[2001:db8::1]":     {"[2001:db8::1]"},
+		"web[001:002].host": {"web001.host", "web002.host"},
+	}
+	for pattern, expected := range cases {
+		if actual, err := expandHostPattern(pattern); err != nil || !reflect.DeepEqual(actual, expected) {
+			t.Errorf("expandHostPattern(%q) = %v, %v, expected %v", pattern, actual, err, expected)
+		}
+	}
+}
+
+const testInventoryYAML = `
+all:
+  hosts:
+    mail.example.com:
+      ansible_user: root
+  vars:
+    env: production
+  children:
+    datacenter:
+      vars:
+        location: Lausanne
+      children:
+        webservers:
+          hosts:
+            web[01:03].example.com:
+              http_port: 80
+          vars:
+            ntp_server: ntp.example.com
+            max_clients: 200
+        dbservers:
+          hosts:
+            db-[a:b].example.com:
+              ansible_port: 2222
+              backup: true
+    webservers:
+      hosts:
+        web01.example.com:
+          ansible_host: 10.0.0.1
+`
+
+func TestParseInventoryYAML(t *testing.T) {
+	layout, err := parseInventoryFile("yaml", testInventoryYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected, err := parseInventoryFile("ini", `
+mail.example.com ansible_user=root
+
+[webservers]
+web[01:03].example.com http_port=80
+web01.example.com ansible_host=10.0.0.1
+
+[dbservers]
+db-[a:b].example.com:2222 backup=True
+
+[webservers:vars]
+ntp_server=ntp.example.com
+max_clients=200
+
+[datacenter:children]
+webservers
+dbservers
+
+[datacenter:vars]
+location=Lausanne
+
+[all:vars]
+env=production
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(layout, expected) {
+		t.Fatalf("Unexpected layout\n%s\nexpected\n%s", renderInventoryINI(layout), renderInventoryINI(expected))
+	}
+}
+
+func TestParseInventoryYAMLErrors(t *testing.T) {
+	cases := map[string]string{
+		"all: [a, b]\n":                                          "Group all must be a mapping with hosts, vars and children",
+		"web:\n  host:\n    web01:\n":                            "Group web has an invalid key host, expected hosts, vars or children",
+		"web:\n  hosts: [web01]\n":                               "Hosts of group web must be a mapping",
+		"web:\n  hosts:\n    web01: [a]\n":                       "Variables of host web01 must be a mapping",
+		"web:\n  children:\n    all:\n":                          "Group all cannot be a child of web",
+		"ungrouped:\n  vars:\n    a: 1\n":                        "Variables of group ungrouped are not supported",
+		"a:\n  children:\n    b:\n      children:\n        a:\n": "Groups hierarchy has a cycle: a -> b -> a",
+		"web:\n  hosts:\n    web[a:z][0:999]:\n":                 `host pattern "web[a:z][0:999]" expands to more than 10000 hosts`,
+		"- a\n":                                                  "yaml: unmarshal errors:\n  line 1: cannot unmarshal !!seq into map[string]interface {}",
+	}
+	for content, expected := range cases {
+		if _, err := parseInventoryFile("yaml", content); err == nil || err.Error() != expected {
+			t.Errorf("parseInventoryFile(%q) returned error %v, expected %q", content, err, expected)
+		}
+	}
+	if _, err := parseInventoryFile("toml", ""); err == nil || err.Error() != `Unsupported inventory format "toml", expected one of: ini, yaml` {
+		t.Errorf("Unexpected error %v", err)
+	}
+}
+
+func TestRenderInventory(t *testing.T) {
+	layout, err := parseInventoryFile("ini", testInventoryINI)
+	if err != nil {
+		t.Fatal(err)
+	}
+	layout.Hosts["mail.example.com"].Variables["quotes"] = `it's "quoted"`
+	layout.Hosts["mail.example.com"].Variables["flag"] = "True"
+	layout.Hosts["mail.example.com"].Variables["empty"] = ""
+	layout.Hosts["mail.example.com"].Variables["nested"] = map[string]interface{}{"a": []interface{}{float64(1), "b"}}
+	layout.Groups["dbservers"].Variables["comment"] = "a # b"
+
+	content := renderInventoryINI(layout)
+	if parsed, err := parseInventoryFile("ini", content); err != nil || !reflect.DeepEqual(parsed, layout) {
+		t.Errorf("INI rendering does not round-trip (%v)\n%s", err, content)
+	}
+	if !strings.HasPrefix(content, `mail.example.com ansible_user=root empty='' flag='"True"' nested='{"a":[1,"b"]}' quotes="it's \"quoted\""`) {
+		t.Errorf("Unexpected INI rendering\n%s", content)
+	}
+
+	if content, err = renderInventoryYAML(layout); err != nil {
+		t.Fatal(err)
+	}
+	if parsed, err := parseInventoryFile("yaml", content); err != nil || !reflect.DeepEqual(parsed, layout) {
+		t.Errorf("YAML rendering does not round-trip (%v)\n%s", err, content)
+	}
+}
@@ -0,0 +1,428 @@
+package awx
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// inventoryLayout is the structure of an inventory: its groups and hosts, their variables and relations, by name.
+type inventoryLayout struct {
+	// Variables of the implicit group all, merged into the variables of the inventory (nil to leave them as is)
+	Variables map[string]interface{}
+	Groups    map[string]*inventoryLayoutGroup
+	Hosts     map[string]*inventoryLayoutHost
+}
+
+type inventoryLayoutGroup struct {
+	Variables map[string]interface{}
+	Children  []string
+}
+
+type inventoryLayoutHost struct {
+	Variables map[string]interface{}
+	Groups    []string
+	Enabled   bool
+}
+
+func newInventoryLayout() *inventoryLayout {
+	return &inventoryLayout{
+		Groups: map[string]*inventoryLayoutGroup{},
+		Hosts:  map[string]*inventoryLayoutHost{},
+	}
+}
+
+// group returns the group name, added if missing.
+func (l *inventoryLayout) group(name string) *inventoryLayoutGroup {
+	g, ok := l.Groups[name]
+	if !ok {
+		g = &inventoryLayoutGroup{Variables: map[string]interface{}{}, Children: []string{}}
+		l.Groups[name] = g
+	}
+	return g
+}
+
+// host returns the host name, added (enabled) if missing.
+func (l *inventoryLayout) host(name string) *inventoryLayoutHost {
+	h, ok := l.Hosts[name]
+	if !ok {
+		h = &inventoryLayoutHost{Variables: map[string]interface{}{}, Groups: []string{}, Enabled: true}
+		l.Hosts[name] = h
+	}
+	return h
+}
+
+// normalize sorts and deduplicates the relations so layouts can be compared.
+func (l *inventoryLayout) normalize() {
+	for _, g := range l.Groups {
+		g.Children = uniqueSortedStrings(g.Children)
+	}
+	for _, h := range l.Hosts {
+		h.Groups = uniqueSortedStrings(h.Groups)
+	}
+}
+
+// validate checks the relations reference declared groups and the groups hierarchy has no cycle.
+func (l *inventoryLayout) validate() error {
+	for _, name := range sortedGroupNames(l.Groups) {
+		for _, child := range l.Groups[name].Children {
+			if _, ok := l.Groups[child]; !ok {
+				return fmt.Errorf("Group %s has an undeclared child group %s", name, child)
+			}
+		}
+	}
+	for _, name := range sortedHostNames(l.Hosts) {
+		for _, group := range l.Hosts[name].Groups {
+			if _, ok := l.Groups[group]; !ok {
+				return fmt.Errorf("Host %s is member of an undeclared group %s", name, group)
+			}
+		}
+	}
+	children := map[string][]string{}
+	for name, g := range l.Groups {
+		children[name] = g.Children
+	}
+	if cycle := findGroupCycle(children); cycle != nil {
+		return fmt.Errorf("Groups hierarchy has a cycle: %s", strings.Join(cycle, " -> "))
+	}
+	return nil
+}
+
+// findGroupCycle returns a cycle of the groups hierarchy (the first group repeated at the end), nil if there is none.
+func findGroupCycle(children map[string][]string) []string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := map[string]int{}
+	var path []string
+	var visit func(name string) []string
+	visit = func(name string) []string {
+		state[name] = visiting
+		path = append(path, name)
+		for _, child := range children[name] {
+			switch state[child] {
+			case visiting:
+				for i, n := range path {
+					if n == child {
+						return append(append([]string{}, path[i:]...), child)
+					}
+				}
+			case unvisited:
+				if cycle := visit(child); cycle != nil {
+					return cycle
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = visited
+		return nil
+	}
+	names := make([]string, 0, len(children))
+	for name := range children {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if state[name] == unvisited {
+			if cycle := visit(name); cycle != nil {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
+
+// inventoryLayoutIDs maps the names of the groups and hosts of an inventory to their IDs.
+type inventoryLayoutIDs struct {
+	Groups map[string]int
+	Hosts  map[string]int
+}
+
+type inventoryTreeNode struct {
+	ID       int                 `json:"id"`
+	Name     string              `json:"name"`
+	Children []inventoryTreeNode `json:"children"`
+}
+
+type inventoryObject struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	Variables string `json:"variables"`
+	Enabled   bool   `json:"enabled"`
+}
+
+// readInventoryLayout fetches the groups (with their hierarchy from the tree) and the hosts of an inventory.
+func (c *Client) readInventoryLayout(inventoryID int) (*inventoryLayout, *inventoryLayoutIDs, error) {
+	layout := newInventoryLayout()
+	ids := &inventoryLayoutIDs{Groups: map[string]int{}, Hosts: map[string]int{}}
+
+	var groups []inventoryObject
+	if err := c.apiList(fmt.Sprintf("/api/v2/inventories/%d/groups/", inventoryID), nil, &groups); err != nil {
+		return nil, nil, err
+	}
+	groupNames := map[int]string{}
+	for _, g := range groups {
+		variables, err := decodeLayoutVariables(g.Variables)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Variables of group %s: %s", g.Name, err)
+		}
+		layout.group(g.Name).Variables = variables
+		ids.Groups[g.Name] = g.ID
+		groupNames[g.ID] = g.Name
+	}
+
+	var tree []inventoryTreeNode
+	if err := c.apiGet(fmt.Sprintf("/api/v2/inventories/%d/tree/", inventoryID), &tree, nil); err != nil {
+		return nil, nil, err
+	}
+	var walk func(nodes []inventoryTreeNode)
+	walk = func(nodes []inventoryTreeNode) {
+		for _, node := range nodes {
+			for _, child := range node.Children {
+				layout.group(node.Name).Children = append(layout.group(node.Name).Children, child.Name)
+			}
+			walk(node.Children)
+		}
+	}
+	walk(tree)
+
+	var hosts []inventoryObject
+	if err := c.apiList(fmt.Sprintf("/api/v2/inventories/%d/hosts/", inventoryID), nil, &hosts); err != nil {
+		return nil, nil, err
+	}
+	hostNames := map[int]string{}
+	for _, h := range hosts {
+		variables, err := decodeLayoutVariables(h.Variables)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Variables of host %s: %s", h.Name, err)
+		}
+		host := layout.host(h.Name)
+		host.Variables = variables
+		host.Enabled = h.Enabled
+		ids.Hosts[h.Name] = h.ID
+		hostNames[h.ID] = h.Name
+	}
+	for _, g := range groups {
+		members, err := c.apiListIDs(fmt.Sprintf("/api/v2/groups/%d/hosts/", g.ID))
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, id := range members {
+			if name, ok := hostNames[id]; ok {
+				layout.host(name).Groups = append(layout.host(name).Groups, g.Name)
+			}
+		}
+	}
+
+	layout.normalize()
+	return layout, ids, nil
+}
+
+// applyInventoryLayout creates, updates, associates, disassociates and deletes groups and hosts of an inventory so it
+// matches desired. Groups and hosts not in desired are deleted.
+func (c *Client) applyInventoryLayout(inventoryID int, desired *inventoryLayout) error {
+	desired.normalize()
+	if err := desired.validate(); err != nil {
+		return err
+	}
+	current, ids, err := c.readInventoryLayout(inventoryID)
+	if err != nil {
+		return err
+	}
+
+	if desired.Variables != nil {
+		if err := c.mergeInventoryVariables(inventoryID, desired.Variables, nil); err != nil {
+			return err
+		}
+	}
+
+	// Groups and hosts first, the relations need their IDs
+	for _, name := range sortedGroupNames(desired.Groups) {
+		g := desired.Groups[name]
+		variables, err := json.Marshal(g.Variables)
+		if err != nil {
+			return err
+		}
+		if id, ok := ids.Groups[name]; !ok {
+			result := new(inventoryObject)
+			payload := map[string]interface{}{"name": name, "inventory": inventoryID, "variables": string(variables)}
+			if err := c.apiPost("/api/v2/groups/", payload, result); err != nil {
+				return err
+			}
+			ids.Groups[name] = result.ID
+			current.group(name)
+		} else if !reflect.DeepEqual(current.Groups[name].Variables, g.Variables) {
+			if err := c.apiPatch(fmt.Sprintf("/api/v2/groups/%d/", id), map[string]interface{}{"variables": string(variables)}, nil); err != nil {
+				return err
+			}
+		}
+	}
+	for _, name := range sortedHostNames(desired.Hosts) {
+		h := desired.Hosts[name]
+		variables, err := json.Marshal(h.Variables)
+		if err != nil {
+			return err
+		}
+		payload := map[string]interface{}{"variables": string(variables), "enabled": h.Enabled}
+		if id, ok := ids.Hosts[name]; !ok {
+			result := new(inventoryObject)
+			payload["name"] = name
+			payload["inventory"] = inventoryID
+			if err := c.apiPost("/api/v2/hosts/", payload, result); err != nil {
+				return err
+			}
+			ids.Hosts[name] = result.ID
+			current.host(name)
+		} else if !reflect.DeepEqual(current.Hosts[name].Variables, h.Variables) || current.Hosts[name].Enabled != h.Enabled {
+			if err := c.apiPatch(fmt.Sprintf("/api/v2/hosts/%d/", id), payload, nil); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Relations, only the groups whose children or hosts changed are touched. Every disassociation runs before the
+	// first association, AWX refuses a cyclical group association while a parent and its child are being swapped.
+	members := func(layout *inventoryLayout) map[string][]string {
+		m := map[string][]string{}
+		for _, name := range sortedHostNames(layout.Hosts) {
+			for _, group := range layout.Hosts[name].Groups {
+				m[group] = append(m[group], name)
+			}
+		}
+		return m
+	}
+	currentMembers, desiredMembers := members(current), members(desired)
+	for _, disassociate := range []bool{true, false} {
+		for _, name := range sortedGroupNames(desired.Groups) {
+			endpoint := fmt.Sprintf("/api/v2/groups/%d/children/", ids.Groups[name])
+			if err := c.reconcileNames(endpoint, current.Groups[name].Children, desired.Groups[name].Children, ids.Groups, disassociate); err != nil {
+				return err
+			}
+			endpoint = fmt.Sprintf("/api/v2/groups/%d/hosts/", ids.Groups[name])
+			if err := c.reconcileNames(endpoint, currentMembers[name], desiredMembers[name], ids.Hosts, disassociate); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Leftovers
+	for _, name := range sortedHostNames(current.Hosts) {
+		if _, ok := desired.Hosts[name]; !ok {
+			if err := c.apiDelete(fmt.Sprintf("/api/v2/hosts/%d/", ids.Hosts[name])); err != nil && !isNotFound(err) {
+				return err
+			}
+		}
+	}
+	for _, name := range sortedGroupNames(current.Groups) {
+		if _, ok := desired.Groups[name]; !ok {
+			if err := c.apiDelete(fmt.Sprintf("/api/v2/groups/%d/", ids.Groups[name])); err != nil && !isNotFound(err) {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// reconcileNames disassociates the objects of a related list endpoint that are missing from the wanted names, or
+// associates the wanted names missing from the current ones.
+func (c *Client) reconcileNames(endpoint string, current, wanted []string, ids map[string]int, disassociate bool) error {
+	if disassociate {
+		for _, name := range current {
+			if !stringInSlice(name, wanted) {
+				if err := c.apiDisassociate(endpoint, ids[name]); err != nil && !isNotFound(err) {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	for _, name := range wanted {
+		if !stringInSlice(name, current) {
+			if err := c.apiAssociate(endpoint, ids[name]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// mergeInventoryVariables sets the given keys of the variables of an inventory and deletes the removed ones, the other
+// keys are left as is.
+func (c *Client) mergeInventoryVariables(inventoryID int, variables map[string]interface{}, removed []string) error {
+	endpoint := fmt.Sprintf("/api/v2/inventories/%d/", inventoryID)
+	inventory := new(inventoryObject)
+	if err := c.apiGet(endpoint, inventory, nil); err != nil {
+		return err
+	}
+	current, err := decodeLayoutVariables(inventory.Variables)
+	if err != nil {
+		return fmt.Errorf("Variables of inventory %d: %s", inventoryID, err)
+	}
+	changed := false
+	for key, value := range variables {
+		if old, ok := current[key]; !ok || !reflect.DeepEqual(old, value) {
+			current[key] = value
+			changed = true
+		}
+	}
+	for _, key := range removed {
+		if _, ok := current[key]; ok {
+			delete(current, key)
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	b, err := json.Marshal(current)
+	if err != nil {
+		return err
+	}
+	return c.apiPatch(endpoint, map[string]interface{}{"variables": string(b)}, nil)
+}
+
+// decodeLayoutVariables decodes a JSON or YAML variables document into a mapping.
+func decodeLayoutVariables(document string) (map[string]interface{}, error) {
+	parsed, err := parseJSONYaml(document)
+	if err != nil {
+		return nil, err
+	}
+	variables, ok := parsed.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("expected a mapping, got %q", document)
+	}
+	return variables, nil
+}
+
+func sortedGroupNames(groups map[string]*inventoryLayoutGroup) []string {
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func sortedHostNames(hosts map[string]*inventoryLayoutHost) []string {
+	names := make([]string, 0, len(hosts))
+	for name := range hosts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func uniqueSortedStrings(values []string) []string {
+	unique := []string{}
+	for _, value := range values {
+		if !stringInSlice(value, unique) {
+			unique = append(unique, value)
+		}
+	}
+	sort.Strings(unique)
+	return unique
+}
@@ -51,22 +51,40 @@ func Provider() terraform.ResourceProvider {
 			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"awx_inventory":         resourceInventoryObject(),
-			"awx_inventory_group":   resourceInventoryGroupObject(),
-			"awx_host":              resourceHostObject(),
-			"awx_group_association": resourceGroupAssociationObject(),
-			"awx_project":           resourceProjectObject(),
-			"awx_job_template":      resourceJobTemplateObject(),
-			"awx_user":              resourceUserObject(),
-			"awx_team":              resourceTeamObject(),
-			"awx_user_role":         resourceUserRoleObject(),
-			"awx_team_role":         resourceTeamRoleObject(),
-			"awx_organization":      resourceOrganizationObject(),
+			"awx_inventory":               resourceInventoryObject(),
+			"awx_inventory_group":         resourceInventoryGroupObject(),
+			"awx_host":                    resourceHostObject(),
+			"awx_group_association":       resourceGroupAssociationObject(),
+			"awx_project":                 resourceProjectObject(),
+			"awx_job_template":            resourceJobTemplateObject(),
+			"awx_user":                    resourceUserObject(),
+			"awx_team":                    resourceTeamObject(),
+			"awx_user_role":               resourceUserRoleObject(),
+			"awx_team_role":               resourceTeamRoleObject(),
+			"awx_organization":            resourceOrganizationObject(),
+			"awx_schedule":                resourceScheduleObject(),
+			"awx_notification_template":   resourceNotificationTemplateObject(),
+			"awx_notification_attachment": resourceNotificationAttachmentObject(),
+			"awx_label":                   resourceLabelObject(),
+			"awx_instance_group":          resourceInstanceGroupObject(),
+			"awx_execution_environment":   resourceExecutionEnvironmentObject(),
+			"awx_instance":                resourceInstanceObject(),
+			"awx_settings":                resourceSettingsObject(),
+			"awx_settings_ldap":           resourceSettingsLDAPObject(),
+			"awx_application":             resourceApplicationObject(),
+			"awx_token":                   resourceTokenObject(),
+			"awx_organization_members":    resourceOrganizationMembersObject(),
+			"awx_organization_member":     resourceOrganizationMemberObject(),
+			"awx_team_members":            resourceTeamMembersObject(),
+			"awx_inventory_tree":          resourceInventoryTreeObject(),
+			"awx_inventory_file":          resourceInventoryFileObject(),
+			"awx_constructed_inventory":   resourceConstructedInventoryObject(),
 		},
 		DataSourcesMap: map[string]*schema.Resource{
-			"awx_project":      dataSourceProjectObject(),
-			"awx_inventory":    dataSourceInventory(),
-			"awx_job_template": dataSourceJobTemplate(),
+			"awx_project":            dataSourceProjectObject(),
+			"awx_inventory":          dataSourceInventory(),
+			"awx_job_template":       dataSourceJobTemplate(),
+			"awx_inventory_rendered": dataSourceInventoryRendered(),
 		},
 
 		ConfigureFunc: providerConfigure,
@@ -0,0 +1,178 @@
+package awx
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+func resourceApplicationObject() *schema.Resource {
+	return &schema.Resource{
+		Create:        resourceApplicationCreate,
+		Read:          resourceApplicationRead,
+		Delete:        resourceApplicationDelete,
+		Update:        resourceApplicationUpdate,
+		CustomizeDiff: resourceApplicationCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of this application.",
+			},
+			"description": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: "Optional description of this application.",
+			},
+			"organization_id": &schema.Schema{
+				Type:        schema.TypeInt,
+				Required:    true,
+				Description: "Numeric ID of the organization this application belongs to.",
+			},
+			"authorization_grant_type": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"authorization-code", "password"}, false),
+				Description:  "One of: authorization-code, password",
+			},
+			"client_type": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"confidential", "public"}, false),
+				Description:  "One of: confidential, public",
+			},
+			"redirect_uris": &schema.Schema{
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Allowed URIs to redirect to, required by the authorization-code grant type.",
+			},
+			"skip_authorization": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Do not ask the users to authorize the application.",
+			},
+
+			// Computed fields
+			"client_id": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "OAuth2 client ID of this application.",
+			},
+			"client_secret": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "OAuth2 client secret of this (confidential) application, only known when it is created.",
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(1 * time.Minute),
+			Update: schema.DefaultTimeout(1 * time.Minute),
+			Delete: schema.DefaultTimeout(1 * time.Minute),
+		},
+	}
+}
+
+func resourceApplicationCreate(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	var existing []Application
+	err := awx.apiList("/api/v2/applications/", map[string]string{
+		"name":         d.Get("name").(string),
+		"organization": strconv.Itoa(d.Get("organization_id").(int)),
+	}, &existing)
+	if err != nil {
+		return err
+	}
+	if len(existing) >= 1 {
+		return fmt.Errorf("Application with name %s already exists in the organization %d",
+			d.Get("name").(string), d.Get("organization_id").(int))
+	}
+
+	payload := applicationPayload(d)
+	payload["authorization_grant_type"] = d.Get("authorization_grant_type").(string)
+	payload["client_type"] = d.Get("client_type").(string)
+
+	result := new(Application)
+	if err := awx.apiPost("/api/v2/applications/", payload, result); err != nil {
+		return err
+	}
+
+	d.SetId(strconv.Itoa(result.ID))
+	// The secret is only disclosed in the answer to the creation
+	d.Set("client_secret", result.ClientSecret)
+	return resourceApplicationRead(d, m)
+}
+
+func resourceApplicationUpdate(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	if err := awx.apiPatch(fmt.Sprintf("/api/v2/applications/%s/", d.Id()), applicationPayload(d), nil); err != nil {
+		return err
+	}
+	return resourceApplicationRead(d, m)
+}
+
+func resourceApplicationRead(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	result := new(Application)
+	if err := awx.apiGet(fmt.Sprintf("/api/v2/applications/%s/", d.Id()), result, nil); err != nil {
+		if isNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	d = setApplicationResourceData(d, result)
+	return nil
+}
+
+func resourceApplicationDelete(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	if err := awx.apiDelete(fmt.Sprintf("/api/v2/applications/%s/", d.Id())); err != nil && !isNotFound(err) {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
+
+func resourceApplicationCustomizeDiff(d *schema.ResourceDiff, m interface{}) error {
+	if d.Get("authorization_grant_type").(string) == "authorization-code" && len(d.Get("redirect_uris").([]interface{})) == 0 {
+		return fmt.Errorf("redirect_uris is required by the authorization-code grant type")
+	}
+	return nil
+}
+
+func applicationPayload(d *schema.ResourceData) map[string]interface{} {
+	return map[string]interface{}{
+		"name":               d.Get("name").(string),
+		"description":        d.Get("description").(string),
+		"organization":       d.Get("organization_id").(int),
+		"redirect_uris":      strings.Join(expandStringList(d.Get("redirect_uris")), " "),
+		"skip_authorization": d.Get("skip_authorization").(bool),
+	}
+}
+
+func setApplicationResourceData(d *schema.ResourceData, r *Application) *schema.ResourceData {
+	d.Set("name", r.Name)
+	d.Set("description", r.Description)
+	d.Set("organization_id", r.Organization)
+	d.Set("authorization_grant_type", r.AuthorizationGrantType)
+	d.Set("client_type", r.ClientType)
+	d.Set("redirect_uris", strings.Fields(r.RedirectURIs))
+	d.Set("skip_authorization", r.SkipAuthorization)
+	d.Set("client_id", r.ClientID)
+	return d
+}
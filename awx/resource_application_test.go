@@ -0,0 +1,59 @@
+package awx
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// awx_application test case
+func TestAccAWXApplication(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { TestAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccApplicationConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckStateApplication("name", "testacc-app_1"),
+					testAccCheckStateApplication("client_type", "confidential"),
+					testAccCheckStateApplication("redirect_uris.#", "2"),
+					resource.TestCheckResourceAttrSet("awx_application.testacc-app_1", "client_id"),
+					resource.TestCheckResourceAttrSet("awx_application.testacc-app_1", "client_secret"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckStateApplication(skey, svalue string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources["awx_application.testacc-app_1"]
+		if !ok {
+			return fmt.Errorf("awx_application.testacc-app_1 not found")
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		cr := rs.Primary
+
+		if cr.Attributes[skey] != svalue {
+			return fmt.Errorf("%s != %s (actual: %s)", skey, svalue, cr.Attributes[skey])
+		}
+
+		return nil
+	}
+}
+
+const testAccApplicationConfig = `
+resource "awx_application" "testacc-app_1" {
+	name                     = "testacc-app_1"
+	organization_id          = 1
+	authorization_grant_type = "authorization-code"
+	client_type              = "confidential"
+	redirect_uris            = ["https://ci.example.com/callback", "https://ci.example.com/oauth"]
+}
+`
@@ -0,0 +1,211 @@
+package awx
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+func resourceConstructedInventoryObject() *schema.Resource {
+	return &schema.Resource{
+		Create:        resourceConstructedInventoryCreate,
+		Read:          resourceConstructedInventoryRead,
+		Delete:        resourceConstructedInventoryDelete,
+		Update:        resourceConstructedInventoryUpdate,
+		CustomizeDiff: resourceConstructedInventoryCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of this constructed inventory.",
+			},
+			"description": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: "Optional description of this constructed inventory.",
+			},
+			"organization_id": &schema.Schema{
+				Type:        schema.TypeInt,
+				Required:    true,
+				Description: "Numeric ID of the organization this constructed inventory belongs to.",
+			},
+			"input_inventory_ids": &schema.Schema{
+				Type:        schema.TypeList,
+				Required:    true,
+				MinItems:    1,
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+				Description: "Ordered numeric IDs of the inventories the hosts and groups are constructed from (authoritative, the variables of the last ones win).",
+			},
+			"variables": &schema.Schema{
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "",
+				ValidateFunc:     validateJSONYaml,
+				DiffSuppressFunc: suppressEquivalentJSONYaml,
+				Description:      "Variables of the constructed inventory as JSON or YAML.",
+			},
+			"source_vars": &schema.Schema{
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "",
+				ValidateFunc:     validateJSONYaml,
+				DiffSuppressFunc: suppressEquivalentJSONYaml,
+				Description:      "Configuration of the constructed inventory plugin (groups, compose, keyed_groups, ...) as JSON or YAML.",
+			},
+			"limit": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: "Host pattern restricting the hosts of the constructed inventory (e.g. webservers:&production).",
+			},
+			"update_cache_timeout": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Seconds the last update of the constructed inventory source is considered current.",
+			},
+			"verbosity": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntBetween(0, 2),
+				Description:  "Verbosity of the updates of the constructed inventory source, 0 (warning), 1 (info) or 2 (debug).",
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(1 * time.Minute),
+			Update: schema.DefaultTimeout(1 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+	}
+}
+
+func resourceConstructedInventoryCreate(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	if err := awx.ensureConstructedInventories(); err != nil {
+		return err
+	}
+
+	result := new(ConstructedInventory)
+	if err := awx.apiPost("/api/v2/constructed_inventories/", constructedInventoryPayload(d), result); err != nil {
+		return err
+	}
+	d.SetId(strconv.Itoa(result.ID))
+
+	endpoint := fmt.Sprintf("/api/v2/inventories/%d/input_inventories/", result.ID)
+	if err := awx.apiReconcileOrdered(endpoint, expandIDList(d.Get("input_inventory_ids"))); err != nil {
+		return err
+	}
+	return resourceConstructedInventoryRead(d, m)
+}
+
+func resourceConstructedInventoryUpdate(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	if err := awx.apiPatch(fmt.Sprintf("/api/v2/constructed_inventories/%s/", d.Id()), constructedInventoryPayload(d), nil); err != nil {
+		return err
+	}
+	if d.HasChange("input_inventory_ids") {
+		endpoint := fmt.Sprintf("/api/v2/inventories/%s/input_inventories/", d.Id())
+		if err := awx.apiReconcileOrdered(endpoint, expandIDList(d.Get("input_inventory_ids"))); err != nil {
+			return err
+		}
+	}
+	return resourceConstructedInventoryRead(d, m)
+}
+
+func resourceConstructedInventoryRead(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	result := new(ConstructedInventory)
+	if err := awx.apiGet(fmt.Sprintf("/api/v2/constructed_inventories/%s/", d.Id()), result, nil); err != nil {
+		if isNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	d = setConstructedInventoryResourceData(d, result)
+
+	inputs, err := awx.apiListIDs(fmt.Sprintf("/api/v2/inventories/%s/input_inventories/", d.Id()))
+	if err != nil {
+		return err
+	}
+	d.Set("input_inventory_ids", inputs)
+	return nil
+}
+
+func resourceConstructedInventoryDelete(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	if err := awx.apiDelete(fmt.Sprintf("/api/v2/constructed_inventories/%s/", d.Id())); err != nil && !isNotFound(err) {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
+
+// resourceConstructedInventoryCustomizeDiff refuses servers without constructed inventories and input inventories
+// listed more than once, their order matters.
+func resourceConstructedInventoryCustomizeDiff(d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() == "" {
+		if err := m.(*Client).ensureConstructedInventories(); err != nil {
+			return err
+		}
+	}
+	if !d.NewValueKnown("input_inventory_ids") {
+		return nil
+	}
+	seen := map[int]bool{}
+	for _, id := range expandIDList(d.Get("input_inventory_ids")) {
+		if seen[id] && id != 0 {
+			return fmt.Errorf("Input inventory %d is listed more than once", id)
+		}
+		seen[id] = true
+	}
+	return nil
+}
+
+// ensureConstructedInventories returns an error if the server has no constructed inventories.
+func (c *Client) ensureConstructedInventories() error {
+	supported, err := c.supportsConstructedInventories()
+	if err != nil {
+		return err
+	}
+	if !supported {
+		return fmt.Errorf("Constructed inventories require AWX 22 or later")
+	}
+	return nil
+}
+
+func constructedInventoryPayload(d *schema.ResourceData) map[string]interface{} {
+	return map[string]interface{}{
+		"name":                 d.Get("name").(string),
+		"description":          d.Get("description").(string),
+		"organization":         d.Get("organization_id").(int),
+		"variables":            d.Get("variables").(string),
+		"source_vars":          d.Get("source_vars").(string),
+		"limit":                d.Get("limit").(string),
+		"update_cache_timeout": d.Get("update_cache_timeout").(int),
+		"verbosity":            d.Get("verbosity").(int),
+	}
+}
+
+func setConstructedInventoryResourceData(d *schema.ResourceData, r *ConstructedInventory) *schema.ResourceData {
+	d.Set("name", r.Name)
+	d.Set("description", r.Description)
+	d.Set("organization_id", r.Organization)
+	d.Set("variables", r.Variables)
+	d.Set("source_vars", r.SourceVars)
+	d.Set("limit", r.Limit)
+	d.Set("update_cache_timeout", r.UpdateCacheTimeout)
+	d.Set("verbosity", r.Verbosity)
+	return d
+}
@@ -0,0 +1,97 @@
+package awx
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// awx_constructed_inventory test case, against a local stub of the constructed inventories endpoints
+func TestAWXConstructedInventory(t *testing.T) {
+	stub := newConstructedInventoryStub("22.3.0")
+	defer stub.Close()
+	provider := fmt.Sprintf("provider \"awx\" {\n\tendpoint = %q\n}\n", stub.URL)
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: provider + fmt.Sprintf(testAWXConstructedInventoryConfig, "1, 2", "web"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("awx_constructed_inventory.all", "input_inventory_ids.#", "2"),
+					resource.TestCheckResourceAttr("awx_constructed_inventory.all", "input_inventory_ids.0", "1"),
+					resource.TestCheckResourceAttr("awx_constructed_inventory.all", "limit", "web"),
+					resource.TestCheckResourceAttr("awx_constructed_inventory.all", "verbosity", "1"),
+					testCheckConstructedInventoryStubInputs(stub, 1, 2),
+				),
+			},
+			{
+				// The source variables are equivalent, only the order of the inputs changes
+				Config: provider + fmt.Sprintf(testAWXConstructedInventoryConfig, "2, 1, 3", "web"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("awx_constructed_inventory.all", "input_inventory_ids.#", "3"),
+					resource.TestCheckResourceAttr("awx_constructed_inventory.all", "input_inventory_ids.0", "2"),
+					testCheckConstructedInventoryStubInputs(stub, 2, 1, 3),
+				),
+			},
+			{
+				Config:      provider + fmt.Sprintf(testAWXConstructedInventoryConfig, "1, 2, 1", "web"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("Input inventory 1 is listed more than once"),
+			},
+		},
+	})
+}
+
+func TestAWXConstructedInventoryUnsupported(t *testing.T) {
+	stub := newConstructedInventoryStub("21.14.0")
+	defer stub.Close()
+	provider := fmt.Sprintf("provider \"awx\" {\n\tendpoint = %q\n}\n", stub.URL)
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      provider + fmt.Sprintf(testAWXConstructedInventoryConfig, "1", ""),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("Constructed inventories require AWX 22 or later"),
+			},
+		},
+	})
+}
+
+func testCheckConstructedInventoryStubInputs(stub *constructedInventoryStub, expected ...int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		stub.mu.Lock()
+		defer stub.mu.Unlock()
+		id, err := strconv.Atoi(s.RootModule().Resources["awx_constructed_inventory.all"].Primary.ID)
+		if err != nil {
+			return err
+		}
+		if inputs := stub.inputs[id]; !reflect.DeepEqual(inputs, expected) {
+			return fmt.Errorf("Input inventories are %v, expected %v", inputs, expected)
+		}
+		return nil
+	}
+}
+
+const testAWXConstructedInventoryConfig = `
+resource "awx_constructed_inventory" "all" {
+	name                = "all"
+	organization_id     = 1
+	input_inventory_ids = [%s]
+	limit               = %q
+	verbosity           = 1
+	source_vars         = <<EOT
+plugin: constructed
+strict: true
+groups:
+  web: "'web' in group_names"
+EOT
+}
+`
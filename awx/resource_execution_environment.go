@@ -0,0 +1,196 @@
+package awx
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+func resourceExecutionEnvironmentObject() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceExecutionEnvironmentCreate,
+		Read:   resourceExecutionEnvironmentRead,
+		Delete: resourceExecutionEnvironmentDelete,
+		Update: resourceExecutionEnvironmentUpdate,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of this execution environment.",
+			},
+			"description": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: "Optional description of this execution environment.",
+			},
+			"image": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The full image location, including the container registry, image name, and version tag.",
+			},
+			"pull": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "",
+				ValidateFunc: validation.StringInSlice([]string{"", "always", "missing", "never"}, false),
+				Description:  "Pull image before running, one of: always, missing, never (empty for the AWX default).",
+			},
+			"credential_id": &schema.Schema{
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Numeric ID of the container registry credential.",
+			},
+			"organization_id": &schema.Schema{
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Numeric ID of the organization this execution environment belongs to, global if unset.",
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(1 * time.Minute),
+			Update: schema.DefaultTimeout(1 * time.Minute),
+			Delete: schema.DefaultTimeout(1 * time.Minute),
+		},
+	}
+}
+
+func resourceExecutionEnvironmentCreate(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	supported, err := awx.supportsExecutionEnvironments()
+	if err != nil {
+		return err
+	}
+	if !supported {
+		return fmt.Errorf("Execution environments require AWX 18 or later")
+	}
+
+	result := new(ExecutionEnvironment)
+	if err := awx.apiPost("/api/v2/execution_environments/", executionEnvironmentPayload(d), result); err != nil {
+		return err
+	}
+
+	d.SetId(strconv.Itoa(result.ID))
+	return resourceExecutionEnvironmentRead(d, m)
+}
+
+func resourceExecutionEnvironmentUpdate(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	endpoint := fmt.Sprintf("/api/v2/execution_environments/%s/", d.Id())
+	if err := awx.apiPatch(endpoint, executionEnvironmentPayload(d), nil); err != nil {
+		return err
+	}
+	return resourceExecutionEnvironmentRead(d, m)
+}
+
+func resourceExecutionEnvironmentRead(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	result := new(ExecutionEnvironment)
+	if err := awx.apiGet(fmt.Sprintf("/api/v2/execution_environments/%s/", d.Id()), result, nil); err != nil {
+		if isNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	d = setExecutionEnvironmentResourceData(d, result)
+	return nil
+}
+
+func resourceExecutionEnvironmentDelete(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	if err := awx.apiDelete(fmt.Sprintf("/api/v2/execution_environments/%s/", d.Id())); err != nil && !isNotFound(err) {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
+
+func executionEnvironmentPayload(d *schema.ResourceData) map[string]interface{} {
+	payload := map[string]interface{}{
+		"name":         d.Get("name").(string),
+		"description":  d.Get("description").(string),
+		"image":        d.Get("image").(string),
+		"pull":         d.Get("pull").(string),
+		"credential":   nil,
+		"organization": nil,
+	}
+	if credential, ok := d.GetOk("credential_id"); ok {
+		payload["credential"] = credential.(int)
+	}
+	if organization, ok := d.GetOk("organization_id"); ok {
+		payload["organization"] = organization.(int)
+	}
+	return payload
+}
+
+func setExecutionEnvironmentResourceData(d *schema.ResourceData, r *ExecutionEnvironment) *schema.ResourceData {
+	d.Set("name", r.Name)
+	d.Set("description", r.Description)
+	d.Set("image", r.Image)
+	d.Set("pull", r.Pull)
+	d.Set("credential_id", 0)
+	if r.Credential != nil {
+		d.Set("credential_id", *r.Credential)
+	}
+	d.Set("organization_id", 0)
+	if r.Organization != nil {
+		d.Set("organization_id", *r.Organization)
+	}
+	return d
+}
+
+// setExecutionEnvironmentPayload adds the execution environment (key of the resource, field of the api object) to
+// payload when the server supports them, and refuses the settings the server does not support.
+func setExecutionEnvironmentPayload(awx *Client, d *schema.ResourceData, payload map[string]interface{}, key, field string) error {
+	supported, err := awx.supportsExecutionEnvironments()
+	if err != nil {
+		return err
+	}
+	environment := d.Get(key).(int)
+	virtualenv, hasVirtualenv := payload["custom_virtualenv"]
+	if !supported {
+		if environment != 0 {
+			return fmt.Errorf("%s requires AWX 18 or later", key)
+		}
+		return nil
+	}
+	if hasVirtualenv {
+		if virtualenv != "" {
+			return fmt.Errorf("custom_virtualenv is not supported by AWX 18 or later, use %s instead", key)
+		}
+		delete(payload, "custom_virtualenv")
+	}
+	payload[field] = nil
+	if environment != 0 {
+		payload[field] = environment
+	}
+	return nil
+}
+
+// readExecutionEnvironment sets key of the resource from field of the api object at endpoint.
+func readExecutionEnvironment(awx *Client, d *schema.ResourceData, endpoint, key, field string) error {
+	supported, err := awx.supportsExecutionEnvironments()
+	if err != nil || !supported {
+		d.Set(key, 0)
+		return err
+	}
+	var result map[string]interface{}
+	if err := awx.apiGet(endpoint, &result, nil); err != nil {
+		return err
+	}
+	environment := 0
+	if id, ok := result[field].(float64); ok {
+		environment = int(id)
+	}
+	d.Set(key, environment)
+	return nil
+}
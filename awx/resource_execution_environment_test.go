@@ -0,0 +1,65 @@
+package awx
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// awx_execution_environment test case
+func TestAccAWXExecutionEnvironment(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { TestAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccExecutionEnvironmentConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckStateExecutionEnvironment("awx_execution_environment.testacc-ee_1", "image", "quay.io/ansible/awx-ee:latest"),
+					testAccCheckStateExecutionEnvironment("awx_execution_environment.testacc-ee_1", "pull", "missing"),
+					testAccCheckStateExecutionEnvironment("awx_execution_environment.testacc-ee_1", "organization_id", "1"),
+					testAccCheckStateExecutionEnvironment("awx_project.testacc-prj_1", "custom_virtualenv", ""),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckStateExecutionEnvironment(name, skey, svalue string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("%s not found", name)
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		cr := rs.Primary
+
+		if cr.Attributes[skey] != svalue {
+			return fmt.Errorf("%s != %s (actual: %s)", skey, svalue, cr.Attributes[skey])
+		}
+
+		return nil
+	}
+}
+
+const testAccExecutionEnvironmentConfig = `
+resource "awx_execution_environment" "testacc-ee_1" {
+	name            = "testacc-ee_1"
+	image           = "quay.io/ansible/awx-ee:latest"
+	pull            = "missing"
+	organization_id = 1
+}
+
+resource "awx_project" "testacc-prj_1" {
+	name                   = "testacc-prj_1"
+	scm_type               = "git"
+	scm_url                = "https://github.com/ansible/ansible-tower-samples"
+	organization_id        = 1
+	default_environment_id = "${awx_execution_environment.testacc-ee_1.id}"
+}
+`
@@ -5,7 +5,6 @@ import (
 	"strconv"
 	"time"
 
-	awxgo "github.com/davidfischer-ch/awx-go"
 	"github.com/hashicorp/terraform/helper/schema"
 )
 
@@ -49,7 +48,7 @@ func resourceGroupAssociationObject() *schema.Resource {
 }
 
 func resourceGroupAssociationCreate(d *schema.ResourceData, m interface{}) error {
-	awx := m.(*awxgo.AWX)
+	awx := m.(*Client)
 	awxServiceHost := awx.HostService
 	awxServiceGroup := awx.GroupService
 	var id, inv int
@@ -92,7 +91,7 @@ func resourceGroupAssociationRead(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceGroupAssociationDelete(d *schema.ResourceData, m interface{}) error {
-	awx := m.(*awxgo.AWX)
+	awx := m.(*Client)
 	awxServiceHost := awx.HostService
 	awxServiceGroup := awx.GroupService
 	var id, inv int
@@ -15,6 +15,8 @@ func resourceHostObject() *schema.Resource {
 		Delete: resourceHostDelete,
 		Update: resourceHostUpdate,
 
+		CustomizeDiff: resourceHostCustomizeDiff,
+
 		Schema: map[string]*schema.Schema{
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
@@ -30,14 +32,16 @@ func resourceHostObject() *schema.Resource {
 				Required: true,
 			},
 			"group_ids": &schema.Schema{
-				Type:     schema.TypeList,
-				Elem:     &schema.Schema{Type: schema.TypeInt},
-				Optional: true,
+				Type:        schema.TypeSet,
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+				Set:         schema.HashInt,
+				Optional:    true,
+				Description: "Numeric IDs of the groups the host is a direct member of (authoritative once set, an empty set removes the host from all its groups). Leave it unset to manage the memberships with awx_group_association.",
 			},
 			"enabled": &schema.Schema{
 				Type:     schema.TypeBool,
 				Optional: true,
-				Default:  "",
+				Default:  true,
 			},
 			"instance_id": &schema.Schema{
 				Type:     schema.TypeString,
@@ -45,10 +49,28 @@ func resourceHostObject() *schema.Resource {
 				Default:  "",
 			},
 			"variables": &schema.Schema{
-				Type:      schema.TypeString,
-				Optional:  true,
-				Default:   "",
-				StateFunc: normalizeJSONYaml,
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "",
+				ValidateFunc:     validateJSONYaml,
+				DiffSuppressFunc: suppressEquivalentJSONYaml,
+				ConflictsWith:    []string{"variables_map"},
+			},
+			"variables_map": &schema.Schema{
+				Type:             schema.TypeMap,
+				Optional:         true,
+				Elem:             &schema.Schema{Type: schema.TypeString},
+				ConflictsWith:    []string{"variables"},
+				ValidateFunc:     validateJSONValues,
+				DiffSuppressFunc: suppressEquivalentJSONValue,
+				Description:      "Variables as an object, values are JSON encoded (e.g. jsonencode(22)), conflicts with variables.",
+			},
+			"variables_managed_keys": &schema.Schema{
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: "Top-level keys of the variables owned by Terraform, the other keys set in AWX are left untouched. Terraform owns the whole document when unset.",
 			},
 		},
 		Importer: &schema.ResourceImporter{
@@ -59,7 +81,7 @@ func resourceHostObject() *schema.Resource {
 
 func resourceHostCreate(d *schema.ResourceData, m interface{}) error {
 
-	awx := m.(*awxgo.AWX)
+	awx := m.(*Client)
 	awxService := awx.HostService
 
 	inv := d.Get("inventory_id").(int)
@@ -71,37 +93,37 @@ func resourceHostCreate(d *schema.ResourceData, m interface{}) error {
 		return fmt.Errorf("Host %s with id %d already exists", res.Results[0].Name, res.Results[0].ID)
 	}
 
+	variables, err := expandVariables(d, "variables", "variables_map")
+	if err != nil {
+		return err
+	}
+	if variables, err = mergeManagedVariables(d, variables, ""); err != nil {
+		return err
+	}
 	result, err := awxService.CreateHost(map[string]interface{}{
 		"name":        d.Get("name").(string),
 		"description": d.Get("description").(string),
 		"inventory":   d.Get("inventory_id").(int),
 		"enabled":     d.Get("enabled").(bool),
 		"instance_id": d.Get("instance_id").(string),
-		"variables":   d.Get("variables").(string),
+		"variables":   variables,
 	}, map[string]string{})
 	if err != nil {
 		return err
 	}
+	d.SetId(strconv.Itoa(result.ID))
 
-	hostID := result.ID
-	if d.IsNewResource() {
-		rawGroups := d.Get("group_ids").([]interface{})
-		for _, v := range rawGroups {
-
-			_, err := awxService.AssociateGroup(hostID, map[string]interface{}{
-				"id": v.(int),
-			}, map[string]string{})
-			if err != nil {
-				return err
-			}
+	if groups, ok := d.GetOk("group_ids"); ok {
+		endpoint := fmt.Sprintf("/api/v2/hosts/%d/groups/", result.ID)
+		if err := awx.apiReconcile(endpoint, expandIDSet(groups)); err != nil {
+			return err
 		}
 	}
-	d.SetId(strconv.Itoa(result.ID))
 	return resourceHostRead(d, m)
 }
 
 func resourceHostUpdate(d *schema.ResourceData, m interface{}) error {
-	awx := m.(*awxgo.AWX)
+	awx := m.(*Client)
 	awxService := awx.HostService
 	_, res, _ := awxService.ListHosts(map[string]string{"id": d.Id()})
 	id, err := strconv.Atoi(d.Id())
@@ -109,6 +131,13 @@ func resourceHostUpdate(d *schema.ResourceData, m interface{}) error {
 		return err
 	}
 	if len(res.Results) >= 1 {
+		variables, err := expandVariables(d, "variables", "variables_map")
+		if err != nil {
+			return err
+		}
+		if variables, err = mergeManagedVariables(d, variables, res.Results[0].Variables); err != nil {
+			return err
+		}
 
 		_, err = awxService.UpdateHost(id, map[string]interface{}{
 			"name":        d.Get("name").(string),
@@ -116,22 +145,16 @@ func resourceHostUpdate(d *schema.ResourceData, m interface{}) error {
 			"inventory":   d.Get("inventory_id").(int),
 			"enabled":     d.Get("enabled").(bool),
 			"instance_id": d.Get("instance_id").(string),
-			"variables":   d.Get("variables").(string),
+			"variables":   variables,
 		}, nil)
 		if err != nil {
 			return err
 		}
 
 		if d.HasChange("group_ids") {
-			rawGroups := d.Get("group_ids").([]interface{})
-			for _, v := range rawGroups {
-
-				_, err := awxService.AssociateGroup(id, map[string]interface{}{
-					"id": v.(int),
-				}, map[string]string{})
-				if err != nil {
-					return err
-				}
+			endpoint := fmt.Sprintf("/api/v2/hosts/%d/groups/", id)
+			if err := awx.apiReconcile(endpoint, expandIDSet(d.Get("group_ids"))); err != nil {
+				return err
 			}
 		}
 		return resourceHostRead(d, m)
@@ -142,7 +165,7 @@ func resourceHostUpdate(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceHostRead(d *schema.ResourceData, m interface{}) error {
-	awx := m.(*awxgo.AWX)
+	awx := m.(*Client)
 	awxService := awx.HostService
 	id, err := strconv.Atoi(d.Id())
 	if err != nil {
@@ -152,12 +175,23 @@ func resourceHostRead(d *schema.ResourceData, m interface{}) error {
 	if err != nil {
 		return err
 	}
+	if len(res.Results) == 0 {
+		d.SetId("")
+		return nil
+	}
 	d = setHostResourceData(d, res.Results[0])
-	return nil
+	if err := readRelatedIDs(awx, d, "group_ids", fmt.Sprintf("/api/v2/hosts/%d/groups/", id)); err != nil {
+		return err
+	}
+	variables, err := filterManagedVariables(d, res.Results[0].Variables)
+	if err != nil {
+		return err
+	}
+	return flattenVariables(d, "variables", "variables_map", variables)
 }
 
 func resourceHostDelete(d *schema.ResourceData, m interface{}) error {
-	awx := m.(*awxgo.AWX)
+	awx := m.(*Client)
 	awxService := awx.HostService
 	id, err := strconv.Atoi(d.Id())
 	if err != nil {
@@ -170,13 +204,19 @@ func resourceHostDelete(d *schema.ResourceData, m interface{}) error {
 	return nil
 }
 
+// resourceHostCustomizeDiff also refuses hosts in smart inventories.
+func resourceHostCustomizeDiff(d *schema.ResourceDiff, m interface{}) error {
+	if err := resourceVariablesCustomizeDiff(d, m); err != nil {
+		return err
+	}
+	return ensureRegularInventoryDiff(d, m, "hosts")
+}
+
 func setHostResourceData(d *schema.ResourceData, r *awxgo.Host) *schema.ResourceData {
 	d.Set("name", r.Name)
 	d.Set("description", r.Description)
 	d.Set("inventory_id", r.Inventory)
 	d.Set("enabled", r.Enabled)
 	d.Set("instance_id", r.InstanceID)
-	d.Set("variables", normalizeJSONYaml(r.Variables))
-	d.Set("group_ids", d.Get("group_ids").([]interface{}))
 	return d
 }
@@ -2,6 +2,7 @@ package awx
 
 import (
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform/helper/resource"
@@ -21,6 +22,149 @@ func TestAccAWXHost(t *testing.T) {
 					testAccCheckStateHost("description", "AWX Acc test host"),
 				),
 			},
+			{
+				Config: testAccHostVariablesMapConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckStateHost("variables", ""),
+					testAccCheckStateHost("variables_map.%", "3"),
+					testAccCheckStateHost("variables_map.api_server_enabled", "false"),
+					testAccCheckStateHost("variables_map.ports", "[22,443]"),
+					testAccCheckStateHost("variables_map.labels", `{"tier":"web"}`),
+				),
+			},
+		},
+	})
+}
+
+// awx_host plan checks of the managed variables, nothing is sent to AWX
+func TestAWXHostManagedVariables(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+provider "awx" {
+	endpoint = "http://127.0.0.1:1"
+}
+
+resource "awx_host" "web1" {
+	name                   = "web1"
+	inventory_id           = 1
+	variables_managed_keys = ["role"]
+	variables              = "role: web\nntp_servers: [ntp1]\n"
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("Variable ntp_servers is not in variables_managed_keys \\(role\\)"),
+			},
+			{
+				Config: `
+provider "awx" {
+	endpoint = "http://127.0.0.1:1"
+}
+
+resource "awx_host" "web1" {
+	name                   = "web1"
+	inventory_id           = 1
+	variables_managed_keys = ["role"]
+	variables_map          = { role = jsonencode("web"), port = jsonencode(22) }
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("Variable port is not in variables_managed_keys \\(role\\)"),
+			},
+		},
+	})
+}
+
+// awx_host group memberships, against a local stub of the inventory endpoints
+func TestAWXHostGroups(t *testing.T) {
+	stub := newInventoryStub(1)
+	defer stub.Close()
+	web := stub.addGroup("web", "")
+	db := stub.addGroup("db", "")
+
+	provider := fmt.Sprintf("provider \"awx\" {\n\tendpoint = %q\n}\n", stub.URL)
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: provider + fmt.Sprintf(testAWXHostGroupsConfig, fmt.Sprintf("group_ids = [%d]", web)),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckInventoryStub(stub,
+						`group db children=[] hosts=[] vars={}`,
+						`group web children=[] hosts=[web1] vars={}`,
+						`host web1 enabled=true vars={}`,
+					),
+					resource.TestCheckResourceAttr("awx_host.web1", "group_ids.#", "1"),
+				),
+			},
+			{
+				// Memberships added out of band are removed
+				PreConfig: func() { stub.groups[db].Hosts[stub.hostID("web1")] = true },
+				Config:    provider + fmt.Sprintf(testAWXHostGroupsConfig, fmt.Sprintf("group_ids = [%d]", db)),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckInventoryStub(stub,
+						`group db children=[] hosts=[web1] vars={}`,
+						`group web children=[] hosts=[] vars={}`,
+						`host web1 enabled=true vars={}`,
+					),
+				),
+			},
+			{
+				// An empty set removes the host from all its groups
+				PreConfig: func() { stub.groups[web].Hosts[stub.hostID("web1")] = true },
+				Config:    provider + fmt.Sprintf(testAWXHostGroupsConfig, "group_ids = []"),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckInventoryStub(stub,
+						`group db children=[] hosts=[] vars={}`,
+						`group web children=[] hosts=[] vars={}`,
+						`host web1 enabled=true vars={}`,
+					),
+					resource.TestCheckResourceAttr("awx_host.web1", "group_ids.#", "0"),
+				),
+			},
+			{
+				// Emptied, the set stays authoritative
+				PreConfig: func() { stub.groups[db].Hosts[stub.hostID("web1")] = true },
+				Config:    provider + fmt.Sprintf(testAWXHostGroupsConfig, "group_ids = []"),
+				Check: testCheckInventoryStub(stub,
+					`group db children=[] hosts=[] vars={}`,
+					`group web children=[] hosts=[] vars={}`,
+					`host web1 enabled=true vars={}`,
+				),
+			},
+		},
+	})
+}
+
+// awx_host without group_ids, the memberships are managed out of band
+func TestAWXHostGroupsUnset(t *testing.T) {
+	stub := newInventoryStub(1)
+	defer stub.Close()
+	web := stub.addGroup("web", "")
+
+	provider := fmt.Sprintf("provider \"awx\" {\n\tendpoint = %q\n}\n", stub.URL)
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: provider + fmt.Sprintf(testAWXHostGroupsConfig, ""),
+			},
+			{
+				// Refreshed and planned again, the membership added out of band is kept
+				PreConfig: func() { stub.groups[web].Hosts[stub.hostID("web1")] = true },
+				Config:    provider + fmt.Sprintf(testAWXHostGroupsConfig, ""),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckInventoryStub(stub,
+						`group web children=[] hosts=[web1] vars={}`,
+						`host web1 enabled=true vars={}`,
+					),
+					resource.TestCheckNoResourceAttr("awx_host.web1", "group_ids.#"),
+				),
+			},
 		},
 	})
 }
@@ -57,3 +201,24 @@ VARIABLES
 
   }
 `
+
+const testAccHostVariablesMapConfig = `
+resource "awx_host" "testacc-host_1" {
+	name         = "testacc-host_1"
+	description  = "AWX Acc test host"
+	inventory_id = "1"
+	variables_map = {
+		api_server_enabled = jsonencode(false)
+		ports              = jsonencode([22, 443])
+		labels             = jsonencode({ tier = "web" })
+	}
+}
+`
+
+const testAWXHostGroupsConfig = `
+resource "awx_host" "web1" {
+	name         = "web1"
+	inventory_id = 1
+	%s
+}
+`
@@ -0,0 +1,259 @@
+package awx
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+func resourceInstanceObject() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceInstanceCreate,
+		Read:   resourceInstanceRead,
+		Delete: resourceInstanceDelete,
+		Update: resourceInstanceUpdate,
+
+		Schema: map[string]*schema.Schema{
+			"hostname": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Hostname of this instance, as other nodes of the mesh reach it.",
+			},
+			"node_type": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "execution",
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"execution", "hop"}, false),
+				Description:  "One of: execution, hop",
+			},
+			"listener_port": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      27199,
+				ValidateFunc: validation.IntBetween(1, 65535),
+				Description:  "Port receptor listens on for incoming connections.",
+			},
+			"peers": &schema.Schema{
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: "Hostnames of the instances this instance connects to (authoritative, sent as their receptor addresses to AWX 24+).",
+			},
+			"peers_from_control_nodes": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether the control nodes connect to this instance.",
+			},
+			"enabled": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether jobs can be dispatched to this instance.",
+			},
+			"managed_by_policy": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether the instance group policies assign this instance.",
+			},
+			"capacity_adjustment": &schema.Schema{
+				Type:         schema.TypeFloat,
+				Optional:     true,
+				Default:      1.0,
+				ValidateFunc: validation.FloatBetween(0, 1),
+				Description:  "Capacity between the CPU-based (0) and the memory-based (1) one.",
+			},
+
+			// Computed fields
+			"node_state": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "State of this instance (e.g. installed, ready, unavailable).",
+			},
+			"capacity": &schema.Schema{
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of forks this instance can run.",
+			},
+			"install_bundle_url": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "URL of the install bundle used to provision this instance.",
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(1 * time.Minute),
+			Update: schema.DefaultTimeout(1 * time.Minute),
+			Delete: schema.DefaultTimeout(1 * time.Minute),
+		},
+	}
+}
+
+func resourceInstanceCreate(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	var existing []Instance
+	if err := awx.apiList("/api/v2/instances/", map[string]string{"hostname": d.Get("hostname").(string)}, &existing); err != nil {
+		return err
+	}
+	if len(existing) >= 1 {
+		return fmt.Errorf("Instance with hostname %s already exists", d.Get("hostname").(string))
+	}
+
+	payload, err := instancePayload(awx, d)
+	if err != nil {
+		return err
+	}
+	payload["hostname"] = d.Get("hostname").(string)
+	payload["node_type"] = d.Get("node_type").(string)
+	payload["node_state"] = "installed"
+
+	result := new(Instance)
+	if err := awx.apiPost("/api/v2/instances/", payload, result); err != nil {
+		return err
+	}
+
+	d.SetId(strconv.Itoa(result.ID))
+	return resourceInstanceRead(d, m)
+}
+
+func resourceInstanceUpdate(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	payload, err := instancePayload(awx, d)
+	if err != nil {
+		return err
+	}
+	if err := awx.apiPatch(fmt.Sprintf("/api/v2/instances/%s/", d.Id()), payload, nil); err != nil {
+		return err
+	}
+	return resourceInstanceRead(d, m)
+}
+
+func resourceInstanceRead(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	result := new(Instance)
+	if err := awx.apiGet(fmt.Sprintf("/api/v2/instances/%s/", d.Id()), result, nil); err != nil {
+		if isNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	if result.NodeState == "deprovisioning" {
+		d.SetId("")
+		return nil
+	}
+	peers, err := awx.instancePeerHostnames(result.Peers)
+	if err != nil {
+		return err
+	}
+	d = setInstanceResourceData(d, result)
+	d.Set("peers", peers)
+	d.Set("install_bundle_url", fmt.Sprintf("%s/api/v2/instances/%d/install_bundle/",
+		strings.TrimRight(awx.Requester.Base, "/"), result.ID))
+	return nil
+}
+
+// resourceInstanceDelete deprovisions the instance, AWX removes it once the mesh acknowledged it.
+func resourceInstanceDelete(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	payload := map[string]interface{}{"node_state": "deprovisioning"}
+	if err := awx.apiPatch(fmt.Sprintf("/api/v2/instances/%s/", d.Id()), payload, nil); err != nil && !isNotFound(err) {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
+
+func instancePayload(awx *Client, d *schema.ResourceData) (map[string]interface{}, error) {
+	peers, err := awx.instancePeers(expandStringSet(d.Get("peers")))
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"listener_port":            d.Get("listener_port").(int),
+		"peers":                    peers,
+		"peers_from_control_nodes": d.Get("peers_from_control_nodes").(bool),
+		"enabled":                  d.Get("enabled").(bool),
+		"managed_by_policy":        d.Get("managed_by_policy").(bool),
+		"capacity_adjustment":      strconv.FormatFloat(d.Get("capacity_adjustment").(float64), 'f', 2, 64),
+	}, nil
+}
+
+// instancePeers returns the peers to send for the given hostnames, the hostnames themselves or their receptor
+// addresses (the canonical one if the instance has many) depending on the server.
+func (c *Client) instancePeers(hostnames []string) (interface{}, error) {
+	supported, err := c.supportsReceptorAddresses()
+	if err != nil || !supported {
+		return hostnames, err
+	}
+	ids := []int{}
+	for _, hostname := range hostnames {
+		var addresses []ReceptorAddress
+		if err := c.apiList("/api/v2/receptor_addresses/", map[string]string{"address": hostname}, &addresses); err != nil {
+			return nil, err
+		}
+		if len(addresses) == 0 {
+			return nil, fmt.Errorf("Peer %s has no receptor address", hostname)
+		}
+		id := addresses[0].ID
+		for _, address := range addresses {
+			if address.Canonical {
+				id = address.ID
+			}
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// instancePeerHostnames decodes the peers of an instance, hostnames or receptor address IDs (AWX 24+).
+func (c *Client) instancePeerHostnames(peers []json.RawMessage) ([]string, error) {
+	hostnames := []string{}
+	for _, peer := range peers {
+		var hostname string
+		if err := json.Unmarshal(peer, &hostname); err == nil {
+			hostnames = append(hostnames, hostname)
+			continue
+		}
+		var id int
+		if err := json.Unmarshal(peer, &id); err != nil {
+			return nil, fmt.Errorf("Unexpected peer %s, expected a hostname or a receptor address ID", peer)
+		}
+		address := new(ReceptorAddress)
+		if err := c.apiGet(fmt.Sprintf("/api/v2/receptor_addresses/%d/", id), address, nil); err != nil {
+			return nil, err
+		}
+		hostnames = append(hostnames, address.Address)
+	}
+	return hostnames, nil
+}
+
+func setInstanceResourceData(d *schema.ResourceData, r *Instance) *schema.ResourceData {
+	d.Set("hostname", r.Hostname)
+	d.Set("node_type", r.NodeType)
+	d.Set("node_state", r.NodeState)
+	if r.ListenerPort != nil {
+		d.Set("listener_port", *r.ListenerPort)
+	}
+	d.Set("peers_from_control_nodes", r.PeersFromControlNodes)
+	d.Set("enabled", r.Enabled)
+	d.Set("managed_by_policy", r.ManagedByPolicy)
+	if adjustment, err := strconv.ParseFloat(r.CapacityAdjustment, 64); err == nil {
+		d.Set("capacity_adjustment", adjustment)
+	}
+	d.Set("capacity", r.Capacity)
+	return d
+}
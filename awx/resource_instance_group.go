@@ -0,0 +1,201 @@
+package awx
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+func resourceInstanceGroupObject() *schema.Resource {
+	return &schema.Resource{
+		Create:        resourceInstanceGroupCreate,
+		Read:          resourceInstanceGroupRead,
+		Delete:        resourceInstanceGroupDelete,
+		Update:        resourceInstanceGroupUpdate,
+		CustomizeDiff: resourceInstanceGroupCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of this instance group.",
+			},
+			"is_container_group": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				ForceNew:    true,
+				Description: "Whether jobs of this group run as pods of a Kubernetes or OpenShift cluster.",
+			},
+			"credential_id": &schema.Schema{
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Numeric ID of the Kubernetes/OpenShift credential (container groups only).",
+			},
+			"pod_spec_override": &schema.Schema{
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "",
+				ValidateFunc:     validateJSONYaml,
+				DiffSuppressFunc: suppressEquivalentJSONYaml,
+				Description:      "Custom pod specification as YAML or JSON (container groups only).",
+			},
+			"policy_instance_percentage": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntBetween(0, 100),
+				Description:  "Minimum percentage of all instances automatically assigned to this group when new instances come online.",
+			},
+			"policy_instance_minimum": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Static minimum number of instances automatically assigned to this group when new instances come online.",
+			},
+			"policy_instance_list": &schema.Schema{
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Hostnames of the instances always assigned to this group.",
+			},
+			"max_concurrent_jobs": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Maximum number of jobs to run concurrently on this group, 0 means no limit.",
+			},
+			"max_forks": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      0,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Maximum number of forks to allow across all jobs running concurrently on this group, 0 means no limit.",
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(1 * time.Minute),
+			Update: schema.DefaultTimeout(1 * time.Minute),
+			Delete: schema.DefaultTimeout(1 * time.Minute),
+		},
+	}
+}
+
+func resourceInstanceGroupCreate(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	var existing []InstanceGroup
+	if err := awx.apiList("/api/v2/instance_groups/", map[string]string{"name": d.Get("name").(string)}, &existing); err != nil {
+		return err
+	}
+	if len(existing) >= 1 {
+		return fmt.Errorf("Instance group with name %s already exists", d.Get("name").(string))
+	}
+
+	result := new(InstanceGroup)
+	if err := awx.apiPost("/api/v2/instance_groups/", instanceGroupPayload(d), result); err != nil {
+		return err
+	}
+
+	d.SetId(strconv.Itoa(result.ID))
+	return resourceInstanceGroupRead(d, m)
+}
+
+func resourceInstanceGroupUpdate(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	if err := awx.apiPatch(fmt.Sprintf("/api/v2/instance_groups/%s/", d.Id()), instanceGroupPayload(d), nil); err != nil {
+		return err
+	}
+	return resourceInstanceGroupRead(d, m)
+}
+
+func resourceInstanceGroupRead(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	result := new(InstanceGroup)
+	if err := awx.apiGet(fmt.Sprintf("/api/v2/instance_groups/%s/", d.Id()), result, nil); err != nil {
+		if isNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	d = setInstanceGroupResourceData(d, result)
+	return nil
+}
+
+func resourceInstanceGroupDelete(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	if err := awx.apiDelete(fmt.Sprintf("/api/v2/instance_groups/%s/", d.Id())); err != nil && !isNotFound(err) {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
+
+func resourceInstanceGroupCustomizeDiff(d *schema.ResourceDiff, m interface{}) error {
+	if d.Get("is_container_group").(bool) {
+		for _, k := range []string{"policy_instance_percentage", "policy_instance_minimum"} {
+			if d.Get(k).(int) != 0 {
+				return fmt.Errorf("%s cannot be set on a container group", k)
+			}
+		}
+		if len(d.Get("policy_instance_list").([]interface{})) > 0 {
+			return fmt.Errorf("policy_instance_list cannot be set on a container group")
+		}
+		return nil
+	}
+	if d.Get("credential_id").(int) != 0 {
+		return fmt.Errorf("credential_id can only be set on a container group")
+	}
+	if d.Get("pod_spec_override").(string) != "" {
+		return fmt.Errorf("pod_spec_override can only be set on a container group")
+	}
+	return nil
+}
+
+func instanceGroupPayload(d *schema.ResourceData) map[string]interface{} {
+	policyInstanceList := []string{}
+	for _, hostname := range d.Get("policy_instance_list").([]interface{}) {
+		policyInstanceList = append(policyInstanceList, hostname.(string))
+	}
+	payload := map[string]interface{}{
+		"name":                       d.Get("name").(string),
+		"is_container_group":         d.Get("is_container_group").(bool),
+		"credential":                 nil,
+		"pod_spec_override":          d.Get("pod_spec_override").(string),
+		"max_concurrent_jobs":        d.Get("max_concurrent_jobs").(int),
+		"max_forks":                  d.Get("max_forks").(int),
+		"policy_instance_percentage": d.Get("policy_instance_percentage").(int),
+		"policy_instance_minimum":    d.Get("policy_instance_minimum").(int),
+		"policy_instance_list":       policyInstanceList,
+	}
+	if credential, ok := d.GetOk("credential_id"); ok {
+		payload["credential"] = credential.(int)
+	}
+	return payload
+}
+
+func setInstanceGroupResourceData(d *schema.ResourceData, r *InstanceGroup) *schema.ResourceData {
+	d.Set("name", r.Name)
+	d.Set("is_container_group", r.IsContainerGroup)
+	if r.Credential != nil {
+		d.Set("credential_id", *r.Credential)
+	} else {
+		d.Set("credential_id", 0)
+	}
+	d.Set("pod_spec_override", r.PodSpecOverride)
+	d.Set("policy_instance_percentage", r.PolicyInstancePercentage)
+	d.Set("policy_instance_minimum", r.PolicyInstanceMinimum)
+	d.Set("policy_instance_list", r.PolicyInstanceList)
+	d.Set("max_concurrent_jobs", r.MaxConcurrentJobs)
+	d.Set("max_forks", r.MaxForks)
+	return d
+}
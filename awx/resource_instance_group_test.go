@@ -0,0 +1,80 @@
+package awx
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// awx_instance_group test case
+func TestAccAWXInstanceGroup(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { TestAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccInstanceGroupConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckStateInstanceGroup("awx_instance_group.testacc-ig_1", "policy_instance_percentage", "50"),
+					testAccCheckStateInstanceGroup("awx_instance_group.testacc-ig_1", "max_forks", "100"),
+					testAccCheckStateInstanceGroup("awx_instance_group.testacc-cg_1", "is_container_group", "true"),
+					testAccCheckStateInstanceGroup("awx_instance_group.testacc-cg_1", "pod_spec_override", testAccInstanceGroupPodSpec),
+					testAccCheckStateInstanceGroup("awx_inventory.testacc-inv_1", "instance_group_ids.#", "2"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckStateInstanceGroup(name, skey, svalue string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("%s not found", name)
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		cr := rs.Primary
+
+		if cr.Attributes[skey] != svalue {
+			return fmt.Errorf("%s != %s (actual: %s)", skey, svalue, cr.Attributes[skey])
+		}
+
+		return nil
+	}
+}
+
+const testAccInstanceGroupPodSpec = `apiVersion: v1
+kind: Pod
+metadata:
+  namespace: awx
+spec:
+  containers:
+  - image: quay.io/ansible/awx-ee:latest
+    name: worker
+`
+
+var testAccInstanceGroupConfig = `
+resource "awx_instance_group" "testacc-ig_1" {
+	name                       = "testacc-ig_1"
+	policy_instance_percentage = 50
+	max_forks                  = 100
+}
+
+resource "awx_instance_group" "testacc-cg_1" {
+	name               = "testacc-cg_1"
+	is_container_group = true
+	pod_spec_override  = <<EOT
+` + testAccInstanceGroupPodSpec + `EOT
+}
+
+resource "awx_inventory" "testacc-inv_1" {
+	name               = "testacc-inv_1"
+	organization_id    = "1"
+	instance_group_ids = ["${awx_instance_group.testacc-cg_1.id}", "${awx_instance_group.testacc-ig_1.id}"]
+}
+`
@@ -0,0 +1,105 @@
+package awx
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// awx_instance test case
+func TestAccAWXInstance(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { TestAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccInstanceConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckStateInstance("awx_instance.testacc-hop_1", "node_type", "hop"),
+					testAccCheckStateInstance("awx_instance.testacc-exec_1", "node_type", "execution"),
+					testAccCheckStateInstance("awx_instance.testacc-exec_1", "peers.#", "1"),
+					testAccCheckStateInstance("awx_instance.testacc-exec_1", "capacity_adjustment", "0.5"),
+				),
+			},
+		},
+	})
+}
+
+// The peers are hostnames up to AWX 23 and receptor address IDs since, against local stubs of both
+func TestAWXInstancePeers(t *testing.T) {
+	for _, version := range []string{"23.5.1", "24.6.1"} {
+		stub := newInstanceStub(version)
+		defer stub.Close()
+		provider := fmt.Sprintf("provider \"awx\" {\n\tendpoint = %q\n}\n", stub.URL)
+
+		resource.UnitTest(t, resource.TestCase{
+			Providers: testAccProviders,
+			Steps: []resource.TestStep{
+				{
+					Config: provider + fmt.Sprintf(testAWXInstancePeersConfig, "[awx_instance.hop_1.hostname]"),
+					Check: resource.ComposeTestCheckFunc(
+						resource.TestCheckResourceAttr("awx_instance.exec_1", "peers.#", "1"),
+						resource.TestCheckResourceAttr("awx_instance.exec_1", "peers.1642140326", "hop-1.example.com"),
+					),
+				},
+				{
+					Config: provider + fmt.Sprintf(testAWXInstancePeersConfig, "[awx_instance.hop_1.hostname, awx_instance.hop_2.hostname]"),
+					Check:  resource.TestCheckResourceAttr("awx_instance.exec_1", "peers.#", "2"),
+				},
+			},
+		})
+	}
+}
+
+func testAccCheckStateInstance(name, skey, svalue string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("%s not found", name)
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		cr := rs.Primary
+
+		if cr.Attributes[skey] != svalue {
+			return fmt.Errorf("%s != %s (actual: %s)", skey, svalue, cr.Attributes[skey])
+		}
+
+		return nil
+	}
+}
+
+const testAccInstanceConfig = `
+resource "awx_instance" "testacc-hop_1" {
+	hostname                 = "testacc-hop-1.example.com"
+	node_type                = "hop"
+	peers_from_control_nodes = true
+}
+
+resource "awx_instance" "testacc-exec_1" {
+	hostname            = "testacc-exec-1.example.com"
+	peers               = ["${awx_instance.testacc-hop_1.hostname}"]
+	capacity_adjustment = 0.5
+}
+`
+
+const testAWXInstancePeersConfig = `
+resource "awx_instance" "hop_1" {
+	hostname  = "hop-1.example.com"
+	node_type = "hop"
+}
+
+resource "awx_instance" "hop_2" {
+	hostname  = "hop-2.example.com"
+	node_type = "hop"
+}
+
+resource "awx_instance" "exec_1" {
+	hostname = "exec-1.example.com"
+	peers    = %s
+}
+`
@@ -3,9 +3,11 @@ package awx
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	awxgo "github.com/davidfischer-ch/awx-go"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 )
 
 func resourceInventoryObject() *schema.Resource {
@@ -15,6 +17,8 @@ func resourceInventoryObject() *schema.Resource {
 		Delete: resourceInventoryDelete,
 		Update: resourceInventoryUpdate,
 
+		CustomizeDiff: resourceInventoryCustomizeDiff,
+
 		Schema: map[string]*schema.Schema{
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
@@ -30,20 +34,48 @@ func resourceInventoryObject() *schema.Resource {
 				Required: true,
 			},
 			"kind": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				Default:  "",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "",
+				ValidateFunc: validation.StringInSlice([]string{"", "smart"}, false),
+				Description:  "Kind of inventory, empty for a regular inventory or smart for an inventory of the hosts matching host_filter.",
 			},
 			"host_filter": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				Default:  "",
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "",
+				ValidateFunc: validateHostFilter,
+				Description:  "Filter of the hosts of a smart inventory (e.g. name__icontains=web and not groups__name=db), required by and only allowed for smart inventories.",
 			},
 			"variables": &schema.Schema{
-				Type:      schema.TypeString,
-				Optional:  true,
-				Default:   "",
-				StateFunc: normalizeJSONYaml,
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "",
+				ValidateFunc:     validateJSONYaml,
+				DiffSuppressFunc: suppressEquivalentJSONYaml,
+				ConflictsWith:    []string{"variables_map"},
+			},
+			"variables_map": &schema.Schema{
+				Type:             schema.TypeMap,
+				Optional:         true,
+				Elem:             &schema.Schema{Type: schema.TypeString},
+				ConflictsWith:    []string{"variables"},
+				ValidateFunc:     validateJSONValues,
+				DiffSuppressFunc: suppressEquivalentJSONValue,
+				Description:      "Variables as an object, values are JSON encoded (e.g. jsonencode(22)), conflicts with variables.",
+			},
+			"variables_managed_keys": &schema.Schema{
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: "Top-level keys of the variables owned by Terraform, the other keys set in AWX are left untouched. Terraform owns the whole document when unset.",
+			},
+			"instance_group_ids": &schema.Schema{
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+				Description: "Ordered numeric IDs of the instance groups of this inventory (authoritative once set, unset keeps the instance groups managed out of band, AWX falls back along this order).",
 			},
 		},
 		Importer: &schema.ResourceImporter{
@@ -53,7 +85,7 @@ func resourceInventoryObject() *schema.Resource {
 }
 
 func resourceInventoryCreate(d *schema.ResourceData, m interface{}) error {
-	awx := m.(*awxgo.AWX)
+	awx := m.(*Client)
 	awxService := awx.InventoriesService
 
 	_, res, _ := awxService.ListInventories(map[string]string{
@@ -64,25 +96,39 @@ func resourceInventoryCreate(d *schema.ResourceData, m interface{}) error {
 		return fmt.Errorf("Inventory %s with id %d already exists", res.Results[0].Name, res.Results[0].ID)
 	}
 
+	variables, err := expandVariables(d, "variables", "variables_map")
+	if err != nil {
+		return err
+	}
+	if variables, err = mergeManagedVariables(d, variables, ""); err != nil {
+		return err
+	}
 	result, err := awxService.CreateInventory(map[string]interface{}{
 		"name":         d.Get("name").(string),
 		"organization": d.Get("organization_id").(string),
 		"description":  d.Get("description").(string),
 		"kind":         d.Get("kind").(string),
 		"host_filter":  d.Get("host_filter").(string),
-		"variables":    d.Get("variables").(string),
+		"variables":    variables,
 	}, map[string]string{})
 	if err != nil {
 		return err
 	}
-
 	d.SetId(strconv.Itoa(result.ID))
+
+	if _, ok := d.GetOk("instance_group_ids"); ok {
+		endpoint := fmt.Sprintf("/api/v2/inventories/%d/instance_groups/", result.ID)
+		if err := awx.apiReconcileOrdered(endpoint, expandIDList(d.Get("instance_group_ids"))); err != nil {
+			return err
+		}
+	}
+
 	return resourceInventoryRead(d, m)
 
 }
 
 func resourceInventoryUpdate(d *schema.ResourceData, m interface{}) error {
-	awx := m.(*awxgo.AWX)
+	awx := m.(*Client)
 	awxService := awx.InventoriesService
 	id, err := strconv.Atoi(d.Id())
 	if err != nil {
@@ -90,6 +136,13 @@ func resourceInventoryUpdate(d *schema.ResourceData, m interface{}) error {
 	}
 	_, res, _ := awxService.ListInventories(map[string]string{"id": d.Id()})
 	if len(res.Results) >= 1 {
+		variables, err := expandVariables(d, "variables", "variables_map")
+		if err != nil {
+			return err
+		}
+		if variables, err = mergeManagedVariables(d, variables, res.Results[0].Variables); err != nil {
+			return err
+		}
 
 		_, err = awxService.UpdateInventory(id, map[string]interface{}{
 			"name":         d.Get("name").(string),
@@ -97,12 +150,19 @@ func resourceInventoryUpdate(d *schema.ResourceData, m interface{}) error {
 			"description":  d.Get("description").(string),
 			"kind":         d.Get("kind").(string),
 			"host_filter":  d.Get("host_filter").(string),
-			"variables":    d.Get("variables").(string),
+			"variables":    variables,
 		}, nil)
 		if err != nil {
 			return err
 		}
 
+		if d.HasChange("instance_group_ids") {
+			endpoint := fmt.Sprintf("/api/v2/inventories/%s/instance_groups/", d.Id())
+			if err := awx.apiReconcileOrdered(endpoint, expandIDList(d.Get("instance_group_ids"))); err != nil {
+				return err
+			}
+		}
+
 		return resourceInventoryRead(d, m)
 	}
 
@@ -111,7 +171,7 @@ func resourceInventoryUpdate(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceInventoryRead(d *schema.ResourceData, m interface{}) error {
-	awx := m.(*awxgo.AWX)
+	awx := m.(*Client)
 	awxService := awx.InventoriesService
 	id, err := strconv.Atoi(d.Id())
 	if err != nil {
@@ -122,11 +182,22 @@ func resourceInventoryRead(d *schema.ResourceData, m interface{}) error {
 		return err
 	}
 	d = setInventoryResourceData(d, r)
+	variables, err := filterManagedVariables(d, r.Variables)
+	if err != nil {
+		return err
+	}
+	if err := flattenVariables(d, "variables", "variables_map", variables); err != nil {
+		return err
+	}
+
+	if err := readRelatedIDs(awx, d, "instance_group_ids", fmt.Sprintf("/api/v2/inventories/%d/instance_groups/", id)); err != nil {
+		return err
+	}
 	return nil
 }
 
 func resourceInventoryDelete(d *schema.ResourceData, m interface{}) error {
-	awx := m.(*awxgo.AWX)
+	awx := m.(*Client)
 	awxService := awx.InventoriesService
 	id, err := strconv.Atoi(d.Id())
 	if err != nil {
@@ -139,12 +210,58 @@ func resourceInventoryDelete(d *schema.ResourceData, m interface{}) error {
 	return nil
 }
 
+// resourceInventoryCustomizeDiff also requires a host_filter for smart inventories, and only for them.
+func resourceInventoryCustomizeDiff(d *schema.ResourceDiff, m interface{}) error {
+	if err := resourceVariablesCustomizeDiff(d, m); err != nil {
+		return err
+	}
+	if !d.NewValueKnown("kind") || !d.NewValueKnown("host_filter") {
+		return nil
+	}
+	smart := d.Get("kind").(string) == "smart"
+	filter := strings.TrimSpace(d.Get("host_filter").(string)) != ""
+	if smart && !filter {
+		return fmt.Errorf("A smart inventory requires a host_filter")
+	}
+	if filter && !smart {
+		return fmt.Errorf("host_filter is only allowed with kind = \"smart\"")
+	}
+	return nil
+}
+
+// ensureRegularInventory returns an error if the inventory is a smart or a constructed inventory, their hosts come
+// from a filter or from input inventories. Inventories not found are left for the apply to report.
+func (c *Client) ensureRegularInventory(inventoryID string, what string) error {
+	inventory := new(awxgo.Inventory)
+	if err := c.apiGet(fmt.Sprintf("/api/v2/inventories/%s/", inventoryID), inventory, nil); err != nil {
+		if isNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	if inventory.Kind == "smart" || inventory.Kind == "constructed" {
+		return fmt.Errorf("Inventory %s is a %s inventory, %s cannot be added to it", inventoryID, inventory.Kind, what)
+	}
+	return nil
+}
+
+// ensureRegularInventoryDiff calls ensureRegularInventory at plan time, when inventory_id is known and set or changed.
+func ensureRegularInventoryDiff(d *schema.ResourceDiff, m interface{}, what string) error {
+	if (d.Id() != "" && !d.HasChange("inventory_id")) || !d.NewValueKnown("inventory_id") {
+		return nil
+	}
+	inventoryID := fmt.Sprint(d.Get("inventory_id"))
+	if inventoryID == "" || inventoryID == "0" {
+		return nil
+	}
+	return m.(*Client).ensureRegularInventory(inventoryID, what)
+}
+
 func setInventoryResourceData(d *schema.ResourceData, r *awxgo.Inventory) *schema.ResourceData {
 	d.Set("name", r.Name)
 	d.Set("organization_id", strconv.Itoa(r.Organization))
 	d.Set("description", r.Description)
 	d.Set("kind", r.Kind)
 	d.Set("host_filter", r.HostFilter)
-	d.Set("variables", normalizeJSONYaml(r.Variables))
 	return d
 }
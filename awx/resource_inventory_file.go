@@ -0,0 +1,220 @@
+package awx
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+func resourceInventoryFileObject() *schema.Resource {
+	return &schema.Resource{
+		Create:        resourceInventoryFileCreate,
+		Read:          resourceInventoryFileRead,
+		Delete:        resourceInventoryFileDelete,
+		Update:        resourceInventoryFileUpdate,
+		CustomizeDiff: resourceInventoryFileCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"inventory_id": &schema.Schema{
+				Type:        schema.TypeInt,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Numeric ID of the inventory, its groups and hosts are all managed by this resource.",
+			},
+			"format": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "ini",
+				ValidateFunc: validation.StringInSlice(inventoryFileFormats, false),
+				Description:  "Format of the content, ini or yaml.",
+			},
+			"content": &schema.Schema{
+				Type:             schema.TypeString,
+				Required:         true,
+				DiffSuppressFunc: suppressEquivalentInventoryFile,
+				Description:      "Ansible inventory file. The variables of group all are merged into the variables of the inventory.",
+			},
+			"groups": &schema.Schema{
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Names of the groups of the inventory.",
+			},
+			"hosts": &schema.Schema{
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Names of the hosts of the inventory.",
+			},
+			"variables_keys": &schema.Schema{
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Keys of the variables of the inventory set by the variables of group all, deleted from the inventory once removed from the content.",
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+	}
+}
+
+func resourceInventoryFileCreate(d *schema.ResourceData, m interface{}) error {
+	if err := applyInventoryFile(d, m); err != nil {
+		return err
+	}
+	d.SetId(strconv.Itoa(d.Get("inventory_id").(int)))
+	return resourceInventoryFileRead(d, m)
+}
+
+func resourceInventoryFileUpdate(d *schema.ResourceData, m interface{}) error {
+	if err := applyInventoryFile(d, m); err != nil {
+		return err
+	}
+	return resourceInventoryFileRead(d, m)
+}
+
+func resourceInventoryFileRead(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return err
+	}
+	current, _, err := awx.readInventoryLayout(id)
+	if err != nil {
+		if isNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+
+	// Only the variables of the inventory set by the file are compared, hosts may be disabled out of band
+	format := d.Get("format").(string)
+	desired, err := parseInventoryFile(format, d.Get("content").(string))
+	if err != nil || d.Get("content").(string) == "" {
+		desired = nil
+	}
+	owned := expandStringList(d.Get("variables_keys"))
+	if desired != nil {
+		owned = append(owned, sortedKeys(desired.Variables)...)
+	}
+	if len(owned) > 0 {
+		inventory := new(inventoryObject)
+		if err := awx.apiGet(fmt.Sprintf("/api/v2/inventories/%d/", id), inventory, nil); err != nil {
+			return err
+		}
+		variables, err := decodeLayoutVariables(inventory.Variables)
+		if err != nil {
+			return fmt.Errorf("Variables of inventory %d: %s", id, err)
+		}
+		if desired != nil && desired.Variables != nil {
+			current.Variables = map[string]interface{}{}
+		}
+		for _, key := range owned {
+			if value, ok := variables[key]; ok {
+				if current.Variables == nil {
+					current.Variables = map[string]interface{}{}
+				}
+				current.Variables[key] = value
+			}
+		}
+	}
+	for _, h := range current.Hosts {
+		h.Enabled = true
+	}
+
+	if desired == nil || !reflect.DeepEqual(desired, current) {
+		content := renderInventoryINI(current)
+		if format == "yaml" {
+			if content, err = renderInventoryYAML(current); err != nil {
+				return err
+			}
+		}
+		d.Set("content", content)
+	}
+	d.Set("inventory_id", id)
+	d.Set("format", format)
+	d.Set("groups", sortedGroupNames(current.Groups))
+	d.Set("hosts", sortedHostNames(current.Hosts))
+	d.Set("variables_keys", sortedKeys(current.Variables))
+	return nil
+}
+
+func resourceInventoryFileDelete(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	if err := awx.applyInventoryLayout(d.Get("inventory_id").(int), newInventoryLayout()); err != nil && !isNotFound(err) {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
+
+// resourceInventoryFileCustomizeDiff reports smart inventories, parse errors, unknown groups and cycles at plan time.
+func resourceInventoryFileCustomizeDiff(d *schema.ResourceDiff, m interface{}) error {
+	if err := ensureRegularInventoryDiff(d, m, "groups and hosts"); err != nil {
+		return err
+	}
+	if !d.NewValueKnown("content") || !d.NewValueKnown("format") {
+		return nil
+	}
+	_, err := parseInventoryFile(d.Get("format").(string), d.Get("content").(string))
+	return err
+}
+
+// applyInventoryFile reconciles the inventory with the content, existing hosts keep their enabled flag and the
+// variables of the inventory removed from group all are deleted.
+func applyInventoryFile(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	inventoryID := d.Get("inventory_id").(int)
+	layout, err := parseInventoryFile(d.Get("format").(string), d.Get("content").(string))
+	if err != nil {
+		return err
+	}
+	removed := []string{}
+	for _, key := range expandStringList(d.Get("variables_keys")) {
+		if _, ok := layout.Variables[key]; !ok {
+			removed = append(removed, key)
+		}
+	}
+	if len(removed) > 0 {
+		if err := awx.mergeInventoryVariables(inventoryID, nil, removed); err != nil {
+			return err
+		}
+	}
+	current, _, err := awx.readInventoryLayout(inventoryID)
+	if err != nil {
+		return err
+	}
+	for name, h := range layout.Hosts {
+		if existing, ok := current.Hosts[name]; ok {
+			h.Enabled = existing.Enabled
+		}
+	}
+	return awx.applyInventoryLayout(inventoryID, layout)
+}
+
+// suppressEquivalentInventoryFile ignores changes of the content that are not changing the inventory (comments,
+// ordering, formatting).
+func suppressEquivalentInventoryFile(k, old, new string, d *schema.ResourceData) bool {
+	format := d.Get("format").(string)
+	oldLayout, err := parseInventoryFile(format, old)
+	if err != nil {
+		return false
+	}
+	newLayout, err := parseInventoryFile(format, new)
+	if err != nil {
+		return false
+	}
+	return reflect.DeepEqual(oldLayout, newLayout)
+}
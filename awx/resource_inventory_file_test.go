@@ -0,0 +1,155 @@
+package awx
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// awx_inventory_file test case, against a local stub of the inventory endpoints
+func TestAWXInventoryFile(t *testing.T) {
+	stub := newInventoryStub(1)
+	defer stub.Close()
+	stub.addHost("stray", "")
+	stub.variables = `{"owner": "ops"}`
+
+	provider := fmt.Sprintf("provider \"awx\" {\n\tendpoint = %q\n}\n", stub.URL)
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: provider + fmt.Sprintf(testAWXInventoryFileConfig, "ini", testAWXInventoryFileINI),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckInventoryStub(stub,
+						`group datacenter children=[web] hosts=[] vars={"location":"Lausanne"}`,
+						`group web children=[] hosts=[web01 web02] vars={"http_port":80}`,
+						`host mail enabled=true vars={"ansible_user":"root"}`,
+						`host web01 enabled=true vars={"ansible_port":2222}`,
+						`host web02 enabled=true vars={"ansible_port":2222}`,
+					),
+					testCheckInventoryStubVariables(stub, `{"env":"production","owner":"ops"}`),
+					testAccCheckStateInventoryFile("groups.#", "2"),
+					testAccCheckStateInventoryFile("hosts.#", "3"),
+					testAccCheckStateInventoryFile("hosts.0", "mail"),
+					testAccCheckStateInventoryFile("variables_keys.#", "1"),
+					testAccCheckStateInventoryFile("variables_keys.0", "env"),
+				),
+			},
+			{
+				// Same inventory, written differently
+				Config:   provider + fmt.Sprintf(testAWXInventoryFileConfig, "ini", testAWXInventoryFileReorderedINI),
+				PlanOnly: true,
+			},
+			{
+				Config: provider + fmt.Sprintf(testAWXInventoryFileConfig, "yaml", testAWXInventoryFileYAML),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckInventoryStub(stub,
+						`group web children=[] hosts=[web01 web03] vars={"http_port":8080}`,
+						`host web01 enabled=true vars={}`,
+						`host web03 enabled=true vars={}`,
+					),
+					// Removed from group all, the other variables are left as is
+					testCheckInventoryStubVariables(stub, `{"owner":"ops"}`),
+					testAccCheckStateInventoryFile("groups.#", "1"),
+					testAccCheckStateInventoryFile("variables_keys.#", "0"),
+				),
+			},
+			{
+				Config:      provider + fmt.Sprintf(testAWXInventoryFileConfig, "ini", "[web]\nweb[3:1]\n"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`Line 2: invalid range in host pattern "web\[3:1\]"`),
+			},
+		},
+	})
+}
+
+func testCheckInventoryStubVariables(stub *inventoryStub, expected string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if actual := stubCanonicalJSON(stub.variables); actual != expected {
+			return fmt.Errorf("Inventory variables are %s, expected %s", actual, expected)
+		}
+		return nil
+	}
+}
+
+func testAccCheckStateInventoryFile(skey, svalue string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources["awx_inventory_file.default"]
+		if !ok {
+			return fmt.Errorf("awx_inventory_file.default not found")
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		cr := rs.Primary
+
+		if cr.Attributes[skey] != svalue {
+			return fmt.Errorf("%s != %s (actual: %s)", skey, svalue, cr.Attributes[skey])
+		}
+
+		return nil
+	}
+}
+
+const testAWXInventoryFileConfig = `
+resource "awx_inventory_file" "default" {
+	inventory_id = 1
+	format       = %q
+	content      = <<EOT
+%sEOT
+}
+`
+
+const testAWXInventoryFileINI = `mail ansible_user=root
+
+[web]
+web[01:02]:2222
+
+[web:vars]
+http_port=80
+
+[datacenter:children]
+web
+
+[datacenter:vars]
+location=Lausanne
+
+[all:vars]
+env=production
+`
+
+const testAWXInventoryFileReorderedINI = `# The same inventory
+[all:vars]
+env = "production"
+
+[datacenter:vars]
+location = Lausanne
+
+[datacenter:children]
+web
+
+[web]
+web01 ansible_port=2222
+web02 ansible_port=2222
+
+[web:vars]
+http_port = 80
+
+[ungrouped]
+mail ansible_user=root
+`
+
+const testAWXInventoryFileYAML = `all:
+  children:
+    web:
+      hosts:
+        web01:
+        web03:
+      vars:
+        http_port: 8080
+`
@@ -3,6 +3,7 @@ package awx
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	awxgo "github.com/davidfischer-ch/awx-go"
 	"github.com/hashicorp/terraform/helper/schema"
@@ -15,6 +16,8 @@ func resourceInventoryGroupObject() *schema.Resource {
 		Update: resourceInventoryGroupUpdate,
 		Delete: resourceInventoryGroupDelete,
 
+		CustomizeDiff: resourceInventoryGroupCustomizeDiff,
+
 		Schema: map[string]*schema.Schema{
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
@@ -31,15 +34,42 @@ func resourceInventoryGroupObject() *schema.Resource {
 				ForceNew: true,
 			},
 			"variables": &schema.Schema{
-				Type:      schema.TypeString,
-				Optional:  true,
-				Default:   "",
-				StateFunc: normalizeJSONYaml,
+				Type:             schema.TypeString,
+				Optional:         true,
+				Default:          "",
+				ValidateFunc:     validateJSONYaml,
+				DiffSuppressFunc: suppressEquivalentJSONYaml,
+				ConflictsWith:    []string{"variables_map"},
+			},
+			"variables_map": &schema.Schema{
+				Type:             schema.TypeMap,
+				Optional:         true,
+				Elem:             &schema.Schema{Type: schema.TypeString},
+				ConflictsWith:    []string{"variables"},
+				ValidateFunc:     validateJSONValues,
+				DiffSuppressFunc: suppressEquivalentJSONValue,
+				Description:      "Variables as an object, values are JSON encoded (e.g. jsonencode(22)), conflicts with variables.",
+			},
+			"variables_managed_keys": &schema.Schema{
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Set:         schema.HashString,
+				Description: "Top-level keys of the variables owned by Terraform, the other keys set in AWX are left untouched. Terraform owns the whole document when unset.",
 			},
 			"child_group_ids": {
-				Type:     schema.TypeList,
-				Elem:     &schema.Schema{Type: schema.TypeInt},
-				Optional: true,
+				Type:        schema.TypeSet,
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+				Set:         schema.HashInt,
+				Optional:    true,
+				Description: "Numeric IDs of the child groups (authoritative once set, an empty set removes all the children).",
+			},
+			"host_ids": {
+				Type:        schema.TypeSet,
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+				Set:         schema.HashInt,
+				Optional:    true,
+				Description: "Numeric IDs of the hosts directly member of the group (authoritative once set, an empty set removes all the hosts, do not combine with group_ids of awx_host).",
 			},
 		},
 		Importer: &schema.ResourceImporter{
@@ -49,7 +79,7 @@ func resourceInventoryGroupObject() *schema.Resource {
 }
 
 func resourceInventoryGroupCreate(d *schema.ResourceData, m interface{}) error {
-	awx := m.(*awxgo.AWX)
+	awx := m.(*Client)
 	awxService := awx.GroupService
 
 	_, res, _ := awxService.ListGroups(map[string]string{
@@ -60,33 +90,43 @@ func resourceInventoryGroupCreate(d *schema.ResourceData, m interface{}) error {
 		return fmt.Errorf("InventoryGroup %s with id %d already exists", res.Results[0].Name, res.Results[0].ID)
 	}
 
+	variables, err := expandVariables(d, "variables", "variables_map")
+	if err != nil {
+		return err
+	}
+	if variables, err = mergeManagedVariables(d, variables, ""); err != nil {
+		return err
+	}
 	result, err := awxService.CreateGroup(map[string]interface{}{
 		"name":        d.Get("name").(string),
 		"description": d.Get("description").(string),
 		"inventory":   d.Get("inventory_id").(string),
-		"variables":   d.Get("variables").(string),
+		"variables":   variables,
 	}, map[string]string{})
 	if err != nil {
 		return err
 	}
+	d.SetId(strconv.Itoa(result.ID))
 
-	if childGroups, ok := d.GetOkExists("child_group_ids"); ok {
-		for _, i := range childGroups.([]interface{}) {
-			_, err := awxService.AddChildGroup(result.ID, i.(int))
-
-			if err != nil {
-				return fmt.Errorf("Failed to add child group %d to group id %d ", i, result.ID)
-			}
+	if children, ok := d.GetOk("child_group_ids"); ok {
+		endpoint := fmt.Sprintf("/api/v2/groups/%d/children/", result.ID)
+		if err := awx.apiReconcile(endpoint, expandIDSet(children)); err != nil {
+			return err
+		}
+	}
+	if hosts, ok := d.GetOk("host_ids"); ok {
+		endpoint := fmt.Sprintf("/api/v2/groups/%d/hosts/", result.ID)
+		if err := awx.apiReconcile(endpoint, expandIDSet(hosts)); err != nil {
+			return err
 		}
 	}
 
-	d.SetId(strconv.Itoa(result.ID))
 	return resourceInventoryGroupRead(d, m)
 
 }
 
 func resourceInventoryGroupUpdate(d *schema.ResourceData, m interface{}) error {
-	awx := m.(*awxgo.AWX)
+	awx := m.(*Client)
 	awxService := awx.GroupService
 	id, err := strconv.Atoi(d.Id())
 	if err != nil {
@@ -94,17 +134,37 @@ func resourceInventoryGroupUpdate(d *schema.ResourceData, m interface{}) error {
 	}
 	_, res, _ := awxService.ListGroups(map[string]string{"id": d.Id()})
 	if len(res.Results) >= 1 {
+		variables, err := expandVariables(d, "variables", "variables_map")
+		if err != nil {
+			return err
+		}
+		if variables, err = mergeManagedVariables(d, variables, res.Results[0].Variables); err != nil {
+			return err
+		}
 
 		_, err = awxService.UpdateGroup(id, map[string]interface{}{
 			"name":        d.Get("name").(string),
 			"description": d.Get("description").(string),
 			"inventory":   d.Get("inventory_id").(string),
-			"variables":   d.Get("variables").(string),
+			"variables":   variables,
 		}, nil)
 		if err != nil {
 			return err
 		}
 
+		if d.HasChange("child_group_ids") {
+			endpoint := fmt.Sprintf("/api/v2/groups/%d/children/", id)
+			if err := awx.apiReconcile(endpoint, expandIDSet(d.Get("child_group_ids"))); err != nil {
+				return err
+			}
+		}
+		if d.HasChange("host_ids") {
+			endpoint := fmt.Sprintf("/api/v2/groups/%d/hosts/", id)
+			if err := awx.apiReconcile(endpoint, expandIDSet(d.Get("host_ids"))); err != nil {
+				return err
+			}
+		}
+
 		return resourceInventoryGroupRead(d, m)
 	}
 	return fmt.Errorf("Group %s with id %d doesn't exist", d.Get("name").(string), id)
@@ -112,7 +172,7 @@ func resourceInventoryGroupUpdate(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceInventoryGroupDelete(d *schema.ResourceData, m interface{}) error {
-	awx := m.(*awxgo.AWX)
+	awx := m.(*Client)
 	awxService := awx.GroupService
 	id, err := strconv.Atoi(d.Id())
 	if err != nil {
@@ -126,27 +186,101 @@ func resourceInventoryGroupDelete(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceInventoryGroupRead(d *schema.ResourceData, m interface{}) error {
-	awx := m.(*awxgo.AWX)
+	awx := m.(*Client)
 	awxService := awx.GroupService
 	id, err := strconv.Atoi(d.Id())
 	if err != nil {
 		return fmt.Errorf("InventoryGroup %d not found", id)
 	}
-	_, res, err := awxService.ListGroups(map[string]string{
-		"name":      d.Get("name").(string),
-		"inventory": d.Get("inventory_id").(string),
-	})
+	_, res, err := awxService.ListGroups(map[string]string{"id": d.Id()})
 	if err != nil {
 		return err
 	}
+	if len(res.Results) == 0 {
+		d.SetId("")
+		return nil
+	}
 	d = setInventoryGroupResourceData(d, res.Results[0])
-	return nil
+
+	if err := readRelatedIDs(awx, d, "child_group_ids", fmt.Sprintf("/api/v2/groups/%d/children/", id)); err != nil {
+		return err
+	}
+	if err := readRelatedIDs(awx, d, "host_ids", fmt.Sprintf("/api/v2/groups/%d/hosts/", id)); err != nil {
+		return err
+	}
+
+	variables, err := filterManagedVariables(d, res.Results[0].Variables)
+	if err != nil {
+		return err
+	}
+	return flattenVariables(d, "variables", "variables_map", variables)
+}
+
+// resourceInventoryGroupCustomizeDiff also refuses groups in smart inventories and child groups that would make the
+// hierarchy cyclic, once the group and its planned children are known.
+func resourceInventoryGroupCustomizeDiff(d *schema.ResourceDiff, m interface{}) error {
+	if err := resourceVariablesCustomizeDiff(d, m); err != nil {
+		return err
+	}
+	if err := ensureRegularInventoryDiff(d, m, "groups"); err != nil {
+		return err
+	}
+	if d.Id() == "" || !d.HasChange("child_group_ids") || !d.NewValueKnown("child_group_ids") || !d.NewValueKnown("inventory_id") {
+		return nil
+	}
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return err
+	}
+	children := expandIDSet(d.Get("child_group_ids"))
+	if intInSlice(0, children) {
+		return nil
+	}
+	return m.(*Client).checkGroupHierarchy(d.Get("inventory_id").(string), id, children)
+}
+
+// checkGroupHierarchy returns an error if setting the children of group id would make the hierarchy of the
+// inventory cyclic.
+func (c *Client) checkGroupHierarchy(inventoryID string, id int, children []int) error {
+	var tree []inventoryTreeNode
+	if err := c.apiGet(fmt.Sprintf("/api/v2/inventories/%s/tree/", inventoryID), &tree, nil); err != nil {
+		return err
+	}
+	graph := map[string][]string{}
+	names := map[string]string{}
+	var walk func(nodes []inventoryTreeNode)
+	walk = func(nodes []inventoryTreeNode) {
+		for _, node := range nodes {
+			key := strconv.Itoa(node.ID)
+			names[key] = node.Name
+			for _, child := range node.Children {
+				graph[key] = append(graph[key], strconv.Itoa(child.ID))
+			}
+			walk(node.Children)
+		}
+	}
+	walk(tree)
+
+	key := strconv.Itoa(id)
+	graph[key] = []string{}
+	for _, child := range children {
+		graph[key] = append(graph[key], strconv.Itoa(child))
+	}
+	cycle := findGroupCycle(graph)
+	if cycle == nil {
+		return nil
+	}
+	for i, k := range cycle {
+		if name, ok := names[k]; ok {
+			cycle[i] = fmt.Sprintf("%s (%s)", name, k)
+		}
+	}
+	return fmt.Errorf("Child groups of group %d would make the groups hierarchy cyclic: %s", id, strings.Join(cycle, " -> "))
 }
 
 func setInventoryGroupResourceData(d *schema.ResourceData, r *awxgo.Group) *schema.ResourceData {
 	d.Set("name", r.Name)
 	d.Set("description", r.Description)
 	d.Set("inventory_id", r.Inventory)
-	d.Set("variables", normalizeJSONYaml(r.Variables))
 	return d
 }
@@ -2,6 +2,7 @@ package awx
 
 import (
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform/helper/resource"
@@ -25,6 +26,122 @@ func TestAccAWXInventoryGroup(t *testing.T) {
 	})
 }
 
+// awx_inventory_group children and hosts, against a local stub of the inventory endpoints
+func TestAWXInventoryGroupHierarchy(t *testing.T) {
+	stub := newInventoryStub(1)
+	defer stub.Close()
+	a := stub.addGroup("a", "")
+	b := stub.addGroup("b", "")
+	web1 := stub.addHost("web1", "")
+
+	provider := fmt.Sprintf("provider \"awx\" {\n\tendpoint = %q\n}\n", stub.URL)
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: provider + fmt.Sprintf(testAWXInventoryGroupHierarchyConfig, a, web1),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckInventoryStub(stub,
+						`group a children=[] hosts=[] vars={}`,
+						`group b children=[] hosts=[] vars={}`,
+						`group parent children=[a] hosts=[web1] vars={}`,
+						`host web1 enabled=true vars={}`,
+					),
+				),
+			},
+			{
+				// Children and hosts added out of band are removed
+				PreConfig: func() {
+					stub.groups[stub.groupID("parent")].Children[b] = true
+					stub.groups[stub.groupID("parent")].Hosts[stub.addHost("web2", "")] = true
+				},
+				Config: provider + fmt.Sprintf(testAWXInventoryGroupHierarchyConfig, b, web1),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckInventoryStub(stub,
+						`group a children=[] hosts=[] vars={}`,
+						`group b children=[] hosts=[] vars={}`,
+						`group parent children=[b] hosts=[web1] vars={}`,
+						`host web1 enabled=true vars={}`,
+						`host web2 enabled=true vars={}`,
+					),
+				),
+			},
+			{
+				// Empty sets remove all the children and hosts, even those added out of band later on
+				Config: provider + testAWXInventoryGroupEmptiedConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckInventoryStub(stub,
+						`group a children=[] hosts=[] vars={}`,
+						`group b children=[] hosts=[] vars={}`,
+						`group parent children=[] hosts=[] vars={}`,
+						`host web1 enabled=true vars={}`,
+						`host web2 enabled=true vars={}`,
+					),
+					resource.TestCheckResourceAttr("awx_inventory_group.parent", "child_group_ids.#", "0"),
+					resource.TestCheckResourceAttr("awx_inventory_group.parent", "host_ids.#", "0"),
+				),
+			},
+			{
+				PreConfig: func() {
+					stub.groups[stub.groupID("parent")].Children[a] = true
+					stub.groups[stub.groupID("parent")].Hosts[web1] = true
+				},
+				Config: provider + testAWXInventoryGroupEmptiedConfig,
+				Check: testCheckInventoryStub(stub,
+					`group a children=[] hosts=[] vars={}`,
+					`group b children=[] hosts=[] vars={}`,
+					`group parent children=[] hosts=[] vars={}`,
+					`host web1 enabled=true vars={}`,
+					`host web2 enabled=true vars={}`,
+				),
+			},
+			{
+				PreConfig:   func() { stub.groups[a].Children[stub.groupID("parent")] = true },
+				Config:      provider + fmt.Sprintf(testAWXInventoryGroupHierarchyConfig, a, web1),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`would make the groups hierarchy cyclic: a \(101\) -> parent \(104\) -> a \(101\)`),
+			},
+		},
+	})
+}
+
+// awx_inventory_group without child_group_ids and host_ids, the hierarchy is managed out of band
+func TestAWXInventoryGroupHierarchyUnset(t *testing.T) {
+	stub := newInventoryStub(1)
+	defer stub.Close()
+	a := stub.addGroup("a", "")
+	web1 := stub.addHost("web1", "")
+
+	provider := fmt.Sprintf("provider \"awx\" {\n\tendpoint = %q\n}\n", stub.URL)
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: provider + testAWXInventoryGroupUnsetConfig,
+			},
+			{
+				// Refreshed and planned again, the child and the host added out of band are kept
+				PreConfig: func() {
+					stub.groups[stub.groupID("parent")].Children[a] = true
+					stub.groups[stub.groupID("parent")].Hosts[web1] = true
+				},
+				Config: provider + testAWXInventoryGroupUnsetConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckInventoryStub(stub,
+						`group a children=[] hosts=[] vars={}`,
+						`group parent children=[a] hosts=[web1] vars={}`,
+						`host web1 enabled=true vars={}`,
+					),
+					resource.TestCheckNoResourceAttr("awx_inventory_group.parent", "child_group_ids.#"),
+					resource.TestCheckNoResourceAttr("awx_inventory_group.parent", "host_ids.#"),
+				),
+			},
+		},
+	})
+}
+
 func testAccCheckStateGroup(skey, svalue string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources["awx_inventory_group.testacc-grp"]
@@ -58,3 +175,28 @@ resource "awx_inventory_group" "testacc-grp" {
 	description = "AWX Acc test group"
 }
 `
+
+const testAWXInventoryGroupHierarchyConfig = `
+resource "awx_inventory_group" "parent" {
+	name            = "parent"
+	inventory_id    = "1"
+	child_group_ids = [%d]
+	host_ids        = [%d]
+}
+`
+
+const testAWXInventoryGroupEmptiedConfig = `
+resource "awx_inventory_group" "parent" {
+	name            = "parent"
+	inventory_id    = "1"
+	child_group_ids = []
+	host_ids        = []
+}
+`
+
+const testAWXInventoryGroupUnsetConfig = `
+resource "awx_inventory_group" "parent" {
+	name         = "parent"
+	inventory_id = "1"
+}
+`
@@ -2,6 +2,7 @@ package awx
 
 import (
 	"fmt"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform/helper/resource"
@@ -31,6 +32,46 @@ func TestAccAWXInventory(t *testing.T) {
 	})
 }
 
+// awx_inventory plan checks of smart inventories
+func TestAWXInventorySmart(t *testing.T) {
+	stub := newInventoryStub(1)
+	defer stub.Close()
+	stub.kind = "smart"
+
+	provider := fmt.Sprintf("provider \"awx\" {\n\tendpoint = %q\n}\n", stub.URL)
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      provider + fmt.Sprintf(testAWXInventorySmartConfig, "smart", ""),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("A smart inventory requires a host_filter"),
+			},
+			{
+				Config:      provider + fmt.Sprintf(testAWXInventorySmartConfig, "", "name=web1"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`host_filter is only allowed with kind = "smart"`),
+			},
+			{
+				Config:      provider + fmt.Sprintf(testAWXInventorySmartConfig, "smart", "(name=web1 or groups__nam=web"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`host_filter: Term groups__nam=web at position 15: unknown field of groups nam, did you mean: name\?`),
+			},
+			{
+				Config:      provider + testAWXInventorySmartHostConfig,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("Inventory 1 is a smart inventory, hosts cannot be added to it"),
+			},
+			{
+				Config:      provider + testAWXInventorySmartGroupConfig,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("Inventory 1 is a smart inventory, groups cannot be added to it"),
+			},
+		},
+	})
+}
+
 func testAccCheckState(skey, svalue string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources["awx_inventory.testacc"]
@@ -58,3 +99,26 @@ resource "awx_inventory" "testacc" {
 	description = "AWX Acc test"
 }
 `
+
+const testAWXInventorySmartConfig = `
+resource "awx_inventory" "smart" {
+	name            = "web"
+	organization_id = "1"
+	kind            = %q
+	host_filter     = %q
+}
+`
+
+const testAWXInventorySmartHostConfig = `
+resource "awx_host" "web1" {
+	name         = "web1"
+	inventory_id = 1
+}
+`
+
+const testAWXInventorySmartGroupConfig = `
+resource "awx_inventory_group" "web" {
+	name         = "web"
+	inventory_id = "1"
+}
+`
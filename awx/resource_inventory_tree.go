@@ -0,0 +1,261 @@
+package awx
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform/config/hcl2shim"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceInventoryTreeObject() *schema.Resource {
+	return &schema.Resource{
+		Create:        resourceInventoryTreeCreate,
+		Read:          resourceInventoryTreeRead,
+		Delete:        resourceInventoryTreeDelete,
+		Update:        resourceInventoryTreeUpdate,
+		CustomizeDiff: resourceInventoryTreeCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"inventory_id": &schema.Schema{
+				Type:        schema.TypeInt,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Numeric ID of the inventory, its groups and hosts are all managed by this resource.",
+			},
+			"group": &schema.Schema{
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Groups of the inventory.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": &schema.Schema{
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Name of the group.",
+						},
+						"variables": &schema.Schema{
+							Type:             schema.TypeString,
+							Optional:         true,
+							Default:          "",
+							ValidateFunc:     validateJSONYaml,
+							DiffSuppressFunc: suppressEquivalentJSONYaml,
+							Description:      "Variables of the group as JSON or YAML.",
+						},
+						"children": &schema.Schema{
+							Type:        schema.TypeSet,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Set:         schema.HashString,
+							Description: "Names of the child groups.",
+						},
+					},
+				},
+			},
+			"host": &schema.Schema{
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Hosts of the inventory.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": &schema.Schema{
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Name of the host.",
+						},
+						"variables": &schema.Schema{
+							Type:             schema.TypeString,
+							Optional:         true,
+							Default:          "",
+							ValidateFunc:     validateJSONYaml,
+							DiffSuppressFunc: suppressEquivalentJSONYaml,
+							Description:      "Variables of the host as JSON or YAML.",
+						},
+						"groups": &schema.Schema{
+							Type:        schema.TypeSet,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Set:         schema.HashString,
+							Description: "Names of the groups the host is a direct member of.",
+						},
+						"enabled": &schema.Schema{
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     true,
+							Description: "Whether the host is available for running jobs.",
+						},
+					},
+				},
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+	}
+}
+
+func resourceInventoryTreeCreate(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	layout, err := expandInventoryTree(d)
+	if err != nil {
+		return err
+	}
+	if err := awx.applyInventoryLayout(d.Get("inventory_id").(int), layout); err != nil {
+		return err
+	}
+
+	d.SetId(strconv.Itoa(d.Get("inventory_id").(int)))
+	return resourceInventoryTreeRead(d, m)
+}
+
+func resourceInventoryTreeUpdate(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	layout, err := expandInventoryTree(d)
+	if err != nil {
+		return err
+	}
+	if err := awx.applyInventoryLayout(d.Get("inventory_id").(int), layout); err != nil {
+		return err
+	}
+	return resourceInventoryTreeRead(d, m)
+}
+
+func resourceInventoryTreeRead(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return err
+	}
+	layout, _, err := awx.readInventoryLayout(id)
+	if err != nil {
+		if isNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	d.Set("inventory_id", id)
+	return flattenInventoryTree(d, layout)
+}
+
+func resourceInventoryTreeDelete(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	if err := awx.applyInventoryLayout(d.Get("inventory_id").(int), newInventoryLayout()); err != nil && !isNotFound(err) {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
+
+// resourceInventoryTreeCustomizeDiff reports smart inventories, unknown groups and cycles at plan time, once all the
+// names are known.
+func resourceInventoryTreeCustomizeDiff(d *schema.ResourceDiff, m interface{}) error {
+	if err := ensureRegularInventoryDiff(d, m, "groups and hosts"); err != nil {
+		return err
+	}
+	layout, err := expandInventoryTree(d)
+	if err != nil {
+		return err
+	}
+	for name, g := range layout.Groups {
+		if name == hcl2shim.UnknownVariableValue || stringInSlice(hcl2shim.UnknownVariableValue, g.Children) {
+			return nil
+		}
+	}
+	for name, h := range layout.Hosts {
+		if name == hcl2shim.UnknownVariableValue || stringInSlice(hcl2shim.UnknownVariableValue, h.Groups) {
+			return nil
+		}
+	}
+	layout.normalize()
+	return layout.validate()
+}
+
+// expandInventoryTree converts the group and host blocks to a layout, names must be unique.
+func expandInventoryTree(d resourceGetter) (*inventoryLayout, error) {
+	layout := newInventoryLayout()
+	for _, raw := range d.Get("group").([]interface{}) {
+		block := raw.(map[string]interface{})
+		name := block["name"].(string)
+		if _, ok := layout.Groups[name]; ok {
+			return nil, fmt.Errorf("Group %s is declared more than once", name)
+		}
+		g := layout.group(name)
+		if variables, err := decodeLayoutVariables(block["variables"].(string)); err == nil {
+			g.Variables = variables
+		}
+		g.Children = expandStringSet(block["children"])
+	}
+	for _, raw := range d.Get("host").([]interface{}) {
+		block := raw.(map[string]interface{})
+		name := block["name"].(string)
+		if _, ok := layout.Hosts[name]; ok {
+			return nil, fmt.Errorf("Host %s is declared more than once", name)
+		}
+		h := layout.host(name)
+		if variables, err := decodeLayoutVariables(block["variables"].(string)); err == nil {
+			h.Variables = variables
+		}
+		h.Groups = expandStringSet(block["groups"])
+		h.Enabled = block["enabled"].(bool)
+	}
+	return layout, nil
+}
+
+// flattenInventoryTree sets the group and host blocks, in the order of the previous state.
+func flattenInventoryTree(d *schema.ResourceData, layout *inventoryLayout) error {
+	old := map[string]interface{}{"group": d.Get("group"), "host": d.Get("host")}
+
+	groups := []interface{}{}
+	for _, name := range sortedGroupNames(layout.Groups) {
+		g := layout.Groups[name]
+		variables, err := encodeLayoutVariables(g.Variables)
+		if err != nil {
+			return err
+		}
+		groups = append(groups, map[string]interface{}{
+			"name":      name,
+			"variables": variables,
+			"children":  g.Children,
+		})
+	}
+	hosts := []interface{}{}
+	for _, name := range sortedHostNames(layout.Hosts) {
+		h := layout.Hosts[name]
+		variables, err := encodeLayoutVariables(h.Variables)
+		if err != nil {
+			return err
+		}
+		hosts = append(hosts, map[string]interface{}{
+			"name":      name,
+			"variables": variables,
+			"groups":    h.Groups,
+			"enabled":   h.Enabled,
+		})
+	}
+
+	if err := d.Set("group", orderLikeConfig(groups, old, "group")); err != nil {
+		return err
+	}
+	return d.Set("host", orderLikeConfig(hosts, old, "host"))
+}
+
+// encodeLayoutVariables encodes variables as JSON, no variables are an empty string.
+func encodeLayoutVariables(variables map[string]interface{}) (string, error) {
+	if len(variables) == 0 {
+		return "", nil
+	}
+	b, err := json.Marshal(variables)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
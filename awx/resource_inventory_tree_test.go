@@ -0,0 +1,233 @@
+package awx
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// awx_inventory_tree test case, against a local stub of the inventory endpoints
+func TestAWXInventoryTree(t *testing.T) {
+	stub := newInventoryStub(1)
+	defer stub.Close()
+	// Created out of band, the tree owns the whole inventory
+	stub.addHost("stray", "")
+
+	provider := fmt.Sprintf("provider \"awx\" {\n\tendpoint = %q\n}\n", stub.URL)
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: provider + testAWXInventoryTreeConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckInventoryStub(stub,
+						`group all_servers children=[db web] hosts=[] vars={}`,
+						`group db children=[] hosts=[db1] vars={"backup":true}`,
+						`group web children=[] hosts=[web1 web2] vars={"http_port":80}`,
+						`host db1 enabled=true vars={}`,
+						`host web1 enabled=true vars={"ansible_host":"10.0.0.1"}`,
+						`host web2 enabled=false vars={}`,
+					),
+					testAccCheckStateInventoryTree("group.#", "3"),
+					testAccCheckStateInventoryTree("group.0.name", "all_servers"),
+					testAccCheckStateInventoryTree("group.1.name", "web"),
+					testAccCheckStateInventoryTree("host.#", "3"),
+					testAccCheckStateInventoryTree("host.2.name", "db1"),
+				),
+			},
+			{
+				Config: provider + testAWXInventoryTreeUpdatedConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testCheckInventoryStub(stub,
+						`group all_servers children=[web] hosts=[] vars={}`,
+						`group web children=[] hosts=[web1 web3] vars={"http_port":8080}`,
+						`host web1 enabled=true vars={"ansible_host":"10.0.0.1"}`,
+						`host web3 enabled=true vars={}`,
+					),
+				),
+			},
+			{
+				Config:      provider + testAWXInventoryTreeCycleConfig,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("Groups hierarchy has a cycle: a -> b -> a"),
+			},
+			{
+				Config:      provider + testAWXInventoryTreeUnknownGroupConfig,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("Host web1 is member of an undeclared group webs"),
+			},
+		},
+	})
+}
+
+// Swapping a parent and its child must not go through a cyclical association
+func TestAWXInventoryTreeSwap(t *testing.T) {
+	stub := newInventoryStub(1)
+	defer stub.Close()
+
+	provider := fmt.Sprintf("provider \"awx\" {\n\tendpoint = %q\n}\n", stub.URL)
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: provider + fmt.Sprintf(testAWXInventoryTreeSwapConfig, "parent", "child"),
+				Check: testCheckInventoryStub(stub,
+					`group child children=[] hosts=[web1] vars={}`,
+					`group parent children=[child] hosts=[] vars={}`,
+					`host web1 enabled=true vars={}`,
+				),
+			},
+			{
+				Config: provider + fmt.Sprintf(testAWXInventoryTreeSwapConfig, "child", "parent"),
+				Check: testCheckInventoryStub(stub,
+					`group child children=[parent] hosts=[] vars={}`,
+					`group parent children=[] hosts=[web1] vars={}`,
+					`host web1 enabled=true vars={}`,
+				),
+			},
+		},
+	})
+}
+
+func testCheckInventoryStub(stub *inventoryStub, expected ...string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if actual := stub.describe(); !reflect.DeepEqual(actual, expected) {
+			return fmt.Errorf("Inventory is\n%v\nexpected\n%v", actual, expected)
+		}
+		return nil
+	}
+}
+
+func testAccCheckStateInventoryTree(skey, svalue string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources["awx_inventory_tree.default"]
+		if !ok {
+			return fmt.Errorf("awx_inventory_tree.default not found")
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		cr := rs.Primary
+
+		if cr.Attributes[skey] != svalue {
+			return fmt.Errorf("%s != %s (actual: %s)", skey, svalue, cr.Attributes[skey])
+		}
+
+		return nil
+	}
+}
+
+const testAWXInventoryTreeConfig = `
+resource "awx_inventory_tree" "default" {
+	inventory_id = 1
+
+	group {
+		name     = "all_servers"
+		children = ["web", "db"]
+	}
+	group {
+		name      = "web"
+		variables = "http_port: 80\n"
+	}
+	group {
+		name      = "db"
+		variables = jsonencode({ backup = true })
+	}
+
+	host {
+		name      = "web1"
+		variables = "---\nansible_host: 10.0.0.1\n"
+		groups    = ["web"]
+	}
+	host {
+		name    = "web2"
+		groups  = ["web"]
+		enabled = false
+	}
+	host {
+		name   = "db1"
+		groups = ["db"]
+	}
+}
+`
+
+const testAWXInventoryTreeUpdatedConfig = `
+resource "awx_inventory_tree" "default" {
+	inventory_id = 1
+
+	group {
+		name     = "all_servers"
+		children = ["web"]
+	}
+	group {
+		name      = "web"
+		variables = "http_port: 8080\n"
+	}
+
+	host {
+		name      = "web1"
+		variables = "---\nansible_host: 10.0.0.1\n"
+		groups    = ["web"]
+	}
+	host {
+		name   = "web3"
+		groups = ["web"]
+	}
+}
+`
+
+const testAWXInventoryTreeCycleConfig = `
+resource "awx_inventory_tree" "default" {
+	inventory_id = 1
+
+	group {
+		name     = "a"
+		children = ["b"]
+	}
+	group {
+		name     = "b"
+		children = ["a"]
+	}
+}
+`
+
+const testAWXInventoryTreeUnknownGroupConfig = `
+resource "awx_inventory_tree" "default" {
+	inventory_id = 1
+
+	group {
+		name = "web"
+	}
+
+	host {
+		name   = "web1"
+		groups = ["webs"]
+	}
+}
+`
+
+const testAWXInventoryTreeSwapConfig = `
+resource "awx_inventory_tree" "default" {
+	inventory_id = 1
+
+	group {
+		name     = "%[1]s"
+		children = ["%[2]s"]
+	}
+	group {
+		name = "%[2]s"
+	}
+
+	host {
+		name   = "web1"
+		groups = ["%[2]s"]
+	}
+}
+`
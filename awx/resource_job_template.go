@@ -2,7 +2,9 @@ package awx
 
 import (
 	"fmt"
+	"log"
 	"strconv"
+	"strings"
 	"time"
 
 	awxgo "github.com/davidfischer-ch/awx-go"
@@ -15,6 +17,8 @@ func resourceJobTemplateObject() *schema.Resource {
 		Read:   resourceJobTemplateRead,
 		Delete: resourceJobTemplateDelete,
 		Update: resourceJobTemplateUpdate,
+
+		CustomizeDiff: resourceJobTemplateCustomizeDiff,
 		Importer: &schema.ResourceImporter{
 			State: importJobTemplateData,
 		},
@@ -77,9 +81,19 @@ func resourceJobTemplateObject() *schema.Resource {
 				Description: "In range 0-5 (Normal, Verbose, More Verbose, Debug, Connection Debug, WinRM Debug)",
 			},
 			"extra_vars": &schema.Schema{
-				Type:     schema.TypeString,
-				Optional: true,
-				Default:  "",
+				Type:          schema.TypeString,
+				Optional:      true,
+				Default:       "",
+				ConflictsWith: []string{"extra_vars_map"},
+			},
+			"extra_vars_map": &schema.Schema{
+				Type:             schema.TypeMap,
+				Optional:         true,
+				Elem:             &schema.Schema{Type: schema.TypeString},
+				ConflictsWith:    []string{"extra_vars"},
+				ValidateFunc:     validateJSONValues,
+				DiffSuppressFunc: suppressEquivalentJSONValue,
+				Description:      "Extra variables as an object, values are JSON encoded (e.g. jsonencode(22)), conflicts with extra_vars.",
 			},
 			"job_tags": &schema.Schema{
 				Type:     schema.TypeString,
@@ -194,7 +208,12 @@ func resourceJobTemplateObject() *schema.Resource {
 				Type:        schema.TypeString,
 				Optional:    true,
 				Default:     "",
-				Description: "Local absolute file path containing a custom Python virtualenv to use.",
+				Description: "Local absolute file path containing a custom Python virtualenv to use (before AWX 18).",
+			},
+			"execution_environment_id": &schema.Schema{
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Numeric ID of the execution environment to run the jobs with (AWX 18 or later).",
 			},
 			"job_slice_count": &schema.Schema{
 				Type:        schema.TypeInt,
@@ -230,6 +249,19 @@ func resourceJobTemplateObject() *schema.Resource {
 				Type:     schema.TypeString,
 				Optional: true,
 			},
+			"label_ids": &schema.Schema{
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+				Set:         schema.HashInt,
+				Description: "Numeric IDs of the labels of this job template (authoritative once set, unset keeps the labels managed out of band).",
+			},
+			"instance_group_ids": &schema.Schema{
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+				Description: "Ordered numeric IDs of the instance groups of this job template (authoritative once set, unset keeps the instance groups managed out of band, AWX falls back along this order).",
+			},
 		},
 
 		Timeouts: &schema.ResourceTimeout{
@@ -240,8 +272,64 @@ func resourceJobTemplateObject() *schema.Resource {
 	}
 }
 
+// resourceJobTemplateCustomizeDiff checks the inventory, the branch and the playbook against the project at plan time.
+func resourceJobTemplateCustomizeDiff(d *schema.ResourceDiff, m interface{}) error {
+	if d.NewValueKnown("inventory_id") && d.Get("inventory_id").(string) == "" && !d.Get("ask_inventory_on_launch").(bool) {
+		return fmt.Errorf("inventory_id is required unless ask_inventory_on_launch is true")
+	}
+
+	// Only check what changes, the project may have changed since the job template was created
+	if !d.NewValueKnown("project_id") {
+		return nil
+	}
+	if d.Id() != "" && !d.HasChange("project_id") && !d.HasChange("playbook") &&
+		!d.HasChange("scm_branch") && !d.HasChange("ask_scm_branch_on_launch") {
+		return nil
+	}
+
+	awx := m.(*Client)
+	projectID := d.Get("project_id").(string)
+	_, res, err := awx.ProjectService.ListProjects(map[string]string{"id": projectID})
+	if err != nil {
+		return err
+	}
+	if len(res.Results) == 0 {
+		return fmt.Errorf("Project %s does not exist", projectID)
+	}
+	project := res.Results[0]
+
+	if !project.AllowOverride {
+		if d.Get("scm_branch").(string) != "" {
+			return fmt.Errorf("scm_branch cannot be set, project %s does not allow overriding the branch (allow_override)", projectID)
+		}
+		if d.Get("ask_scm_branch_on_launch").(bool) {
+			return fmt.Errorf("ask_scm_branch_on_launch cannot be set, project %s does not allow overriding the branch (allow_override)", projectID)
+		}
+	}
+
+	playbook := d.Get("playbook").(string)
+	if !d.NewValueKnown("playbook") || playbook == "" {
+		return nil
+	}
+	var playbooks []string
+	if err := awx.apiGet(fmt.Sprintf("/api/v2/projects/%s/playbooks/", projectID), &playbooks, nil); err != nil {
+		return err
+	}
+	if len(playbooks) == 0 {
+		log.Printf("[WARN] Project %s has no playbook (not synced yet?), not checking playbook %s", projectID, playbook)
+		return nil
+	}
+	if !stringInSlice(playbook, playbooks) {
+		if suggestions := closeMatches(playbook, playbooks, 3); len(suggestions) > 0 {
+			return fmt.Errorf("Playbook %s not found in project %s, did you mean: %s?", playbook, projectID, strings.Join(suggestions, ", "))
+		}
+		return fmt.Errorf("Playbook %s not found in project %s", playbook, projectID)
+	}
+	return nil
+}
+
 func resourceJobTemplateCreate(d *schema.ResourceData, m interface{}) error {
-	awx := m.(*awxgo.AWX)
+	awx := m.(*Client)
 	awxService := awx.JobTemplateService
 	var jobID int
 	var finished time.Time
@@ -281,6 +369,10 @@ func resourceJobTemplateCreate(d *schema.ResourceData, m interface{}) error {
 		}
 	}
 
+	extraVars, err := expandVariables(d, "extra_vars", "extra_vars_map")
+	if err != nil {
+		return err
+	}
 	payload := map[string]interface{}{
 		"name":                     d.Get("name").(string),
 		"description":              d.Get("description").(string),
@@ -293,7 +385,7 @@ func resourceJobTemplateCreate(d *schema.ResourceData, m interface{}) error {
 		"forks":                    d.Get("forks").(int),
 		"limit":                    d.Get("limit").(string),
 		"verbosity":                d.Get("verbosity").(int),
-		"extra_vars":               d.Get("extra_vars").(string),
+		"extra_vars":               extraVars,
 		"job_tags":                 d.Get("job_tags").(string),
 		"force_handlers":           d.Get("force_handlers").(bool),
 		"skip_tags":                d.Get("skip_tags").(string),
@@ -315,17 +407,22 @@ func resourceJobTemplateCreate(d *schema.ResourceData, m interface{}) error {
 		"become_enabled":           d.Get("become_enabled").(bool),
 		"diff_mode":                d.Get("diff_mode").(bool),
 		"allow_simultaneous":       d.Get("allow_simultaneous").(bool),
-		"custom_virtualenv":        AtoipOr(d.Get("custom_virtualenv").(string), nil),
+		"custom_virtualenv":        d.Get("custom_virtualenv").(string),
 		"job_slice_count":          d.Get("job_slice_count").(int),
 		"webhook_service":          d.Get("webhook_service").(string),
 		"webhook_credential":       AtoipOr(d.Get("webhook_credential_id").(string), nil),
 		"vault_credential":         AtoipOr(d.Get("vault_credential_id").(string), nil),
 	}
 
+	if err := setExecutionEnvironmentPayload(awx, d, payload, "execution_environment_id", "execution_environment"); err != nil {
+		return err
+	}
+
 	result, err := awxService.CreateJobTemplate(payload, map[string]string{})
 	if err != nil {
 		return err
 	}
+	d.SetId(strconv.Itoa(result.ID))
 
 	if creds, ok := d.GetOk("extra_credential_ids"); ok {
 		for _, c := range creds.([]interface{}) {
@@ -337,12 +434,25 @@ func resourceJobTemplateCreate(d *schema.ResourceData, m interface{}) error {
 
 	}
 
-	d.SetId(strconv.Itoa(result.ID))
+	if labels, ok := d.GetOk("label_ids"); ok {
+		endpoint := fmt.Sprintf("/api/v2/job_templates/%d/labels/", result.ID)
+		if err := awx.apiReconcile(endpoint, expandIDSet(labels)); err != nil {
+			return err
+		}
+	}
+
+	if _, ok := d.GetOk("instance_group_ids"); ok {
+		endpoint := fmt.Sprintf("/api/v2/job_templates/%d/instance_groups/", result.ID)
+		if err := awx.apiReconcileOrdered(endpoint, expandIDList(d.Get("instance_group_ids"))); err != nil {
+			return err
+		}
+	}
+
 	return resourceJobTemplateRead(d, m)
 }
 
 func resourceJobTemplateUpdate(d *schema.ResourceData, m interface{}) error {
-	awx := m.(*awxgo.AWX)
+	awx := m.(*Client)
 	awxService := awx.JobTemplateService
 	_, res, err := awxService.ListJobTemplates(map[string]string{
 		"id":      d.Id(),
@@ -359,7 +469,11 @@ func resourceJobTemplateUpdate(d *schema.ResourceData, m interface{}) error {
 	if err != nil {
 		return err
 	}
-	result, err := awxService.UpdateJobTemplate(id, map[string]interface{}{
+	extraVars, err := expandVariables(d, "extra_vars", "extra_vars_map")
+	if err != nil {
+		return err
+	}
+	payload := map[string]interface{}{
 		"name":                     d.Get("name").(string),
 		"description":              d.Get("description").(string),
 		"job_type":                 d.Get("job_type").(string),
@@ -371,7 +485,7 @@ func resourceJobTemplateUpdate(d *schema.ResourceData, m interface{}) error {
 		"forks":                    d.Get("forks").(int),
 		"limit":                    d.Get("limit").(string),
 		"verbosity":                d.Get("verbosity").(int),
-		"extra_vars":               d.Get("extra_vars").(string),
+		"extra_vars":               extraVars,
 		"job_tags":                 d.Get("job_tags").(string),
 		"force_handlers":           d.Get("force_handlers").(bool),
 		"skip_tags":                d.Get("skip_tags").(string),
@@ -393,12 +507,17 @@ func resourceJobTemplateUpdate(d *schema.ResourceData, m interface{}) error {
 		"become_enabled":           d.Get("become_enabled").(bool),
 		"diff_mode":                d.Get("diff_mode").(bool),
 		"allow_simultaneous":       d.Get("allow_simultaneous").(bool),
-		"custom_virtualenv":        AtoipOr(d.Get("custom_virtualenv").(string), nil),
+		"custom_virtualenv":        d.Get("custom_virtualenv").(string),
 		"job_slice_count":          d.Get("job_slice_count").(int),
 		"webhook_service":          d.Get("webhook_service").(string),
 		"webhook_credential":       AtoipOr(d.Get("webhook_credential_id").(string), nil),
 		"vault_credential":         AtoipOr(d.Get("vault_credential_id").(string), nil),
-	}, map[string]string{})
+	}
+	if err := setExecutionEnvironmentPayload(awx, d, payload, "execution_environment_id", "execution_environment"); err != nil {
+		return err
+	}
+
+	result, err := awxService.UpdateJobTemplate(id, payload, map[string]string{})
 	if err != nil {
 		return err
 	}
@@ -413,11 +532,25 @@ func resourceJobTemplateUpdate(d *schema.ResourceData, m interface{}) error {
 
 	}
 
+	if d.HasChange("label_ids") {
+		endpoint := fmt.Sprintf("/api/v2/job_templates/%d/labels/", result.ID)
+		if err := awx.apiReconcile(endpoint, expandIDSet(d.Get("label_ids"))); err != nil {
+			return err
+		}
+	}
+
+	if d.HasChange("instance_group_ids") {
+		endpoint := fmt.Sprintf("/api/v2/job_templates/%d/instance_groups/", result.ID)
+		if err := awx.apiReconcileOrdered(endpoint, expandIDList(d.Get("instance_group_ids"))); err != nil {
+			return err
+		}
+	}
+
 	return resourceJobTemplateRead(d, m)
 }
 
 func resourceJobTemplateRead(d *schema.ResourceData, m interface{}) error {
-	awx := m.(*awxgo.AWX)
+	awx := m.(*Client)
 	awxService := awx.JobTemplateService
 	_, res, err := awxService.ListJobTemplates(map[string]string{
 		"id": strconv.Itoa(d.Get("job_id").(int)),
@@ -431,11 +564,24 @@ func resourceJobTemplateRead(d *schema.ResourceData, m interface{}) error {
 		return nil
 	}
 	d = setJobTemplateResourceData(d, res.Results[0])
-	return nil
+	if err := flattenVariables(d, "extra_vars", "extra_vars_map", res.Results[0].ExtraVars); err != nil {
+		return err
+	}
+
+	if err := readRelatedIDs(awx, d, "label_ids", fmt.Sprintf("/api/v2/job_templates/%d/labels/", res.Results[0].ID)); err != nil {
+		return err
+	}
+
+	if err := readRelatedIDs(awx, d, "instance_group_ids", fmt.Sprintf("/api/v2/job_templates/%d/instance_groups/", res.Results[0].ID)); err != nil {
+		return err
+	}
+
+	endpoint := fmt.Sprintf("/api/v2/job_templates/%d/", res.Results[0].ID)
+	return readExecutionEnvironment(awx, d, endpoint, "execution_environment_id", "execution_environment")
 }
 
 func resourceJobTemplateDelete(d *schema.ResourceData, m interface{}) error {
-	awx := m.(*awxgo.AWX)
+	awx := m.(*Client)
 	awxService := awx.JobTemplateService
 	_, res, err := awxService.ListJobTemplates(map[string]string{
 		"id":      d.Id(),
@@ -481,7 +627,6 @@ func setJobTemplateResourceData(d *schema.ResourceData, r *awxgo.JobTemplate) *s
 	d.Set("description", r.Description)
 	d.Set("diff_mode", r.DiffMode)
 	d.Set("diff_mode", r.DiffMode)
-	d.Set("extra_vars", r.ExtraVars)
 	d.Set("force_handlers", r.ForceHandlers)
 	d.Set("forks", r.Forks)
 	d.Set("host_config_key", r.HostConfigKey)
@@ -509,7 +654,7 @@ func setJobTemplateResourceData(d *schema.ResourceData, r *awxgo.JobTemplate) *s
 }
 
 func importJobTemplateData(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
-	awx := m.(*awxgo.AWX)
+	awx := m.(*Client)
 	awxService := awx.JobTemplateService
 
 	id, err := strconv.Atoi(d.Id())
@@ -524,6 +669,14 @@ func importJobTemplateData(d *schema.ResourceData, m interface{}) ([]*schema.Res
 		return nil, err
 	}
 
+	endpoint := fmt.Sprintf("/api/v2/job_templates/%d/", job.ID)
+	if err := readExecutionEnvironment(awx, d, endpoint, "execution_environment_id", "execution_environment"); err != nil {
+		return nil, err
+	}
+	if err := flattenVariables(d, "extra_vars", "extra_vars_map", job.ExtraVars); err != nil {
+		return nil, err
+	}
+
 	resources := []*schema.ResourceData{setJobTemplateResourceData(d, job)}
 
 	return resources, nil
@@ -1,7 +1,11 @@
 package awx
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform/helper/resource"
@@ -28,6 +32,70 @@ func TestAccAWXJobTemplate(t *testing.T) {
 	})
 }
 
+// awx_job_template plan checks, against a local stub of the projects endpoints
+func TestAWXJobTemplatePlan(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/projects/":
+			results := []interface{}{}
+			if r.URL.Query().Get("id") == "1" {
+				results = append(results, map[string]interface{}{"id": 1, "name": "samples", "allow_override": false})
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"count": len(results), "next": nil, "results": results})
+		case "/api/v2/projects/1/playbooks/":
+			json.NewEncoder(w).Encode([]string{"hello_world.yml", "site.yml"})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	config := func(attributes string) string {
+		return fmt.Sprintf(`
+provider "awx" {
+	endpoint = %q
+}
+
+resource "awx_job_template" "alpha" {
+	name     = "alpha"
+	job_type = "run"
+	%s
+}
+`, server.URL, attributes)
+	}
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      config(`project_id = "1"` + "\n" + `inventory_id = "1"` + "\n" + `playbook = "hello_wrld.yml"`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("Playbook hello_wrld.yml not found in project 1, did you mean: hello_world.yml"),
+			},
+			{
+				Config:      config(`project_id = "1"` + "\n" + `inventory_id = "1"` + "\n" + `playbook = "deploy.yml"`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("Playbook deploy.yml not found in project 1$"),
+			},
+			{
+				Config:      config(`project_id = "1"` + "\n" + `inventory_id = "1"` + "\n" + `playbook = "site.yml"` + "\n" + `scm_branch = "devel"`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("project 1 does not allow overriding the branch"),
+			},
+			{
+				Config:      config(`project_id = "1"` + "\n" + `playbook = "site.yml"`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("inventory_id is required unless ask_inventory_on_launch is true"),
+			},
+			{
+				Config:      config(`project_id = "2"` + "\n" + `inventory_id = "1"` + "\n" + `playbook = "site.yml"`),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("Project 2 does not exist"),
+			},
+		},
+	})
+}
+
 func testAccCheckStateJobTemplate(skey, svalue string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources["awx_job_template.alpha"]
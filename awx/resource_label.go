@@ -0,0 +1,117 @@
+package awx
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceLabelObject() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceLabelCreate,
+		Read:   resourceLabelRead,
+		Delete: resourceLabelDelete,
+		Update: resourceLabelUpdate,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of this label.",
+			},
+			"organization_id": &schema.Schema{
+				Type:        schema.TypeInt,
+				Required:    true,
+				Description: "Numeric ID of the organization this label belongs to.",
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(1 * time.Minute),
+			Update: schema.DefaultTimeout(1 * time.Minute),
+			Delete: schema.DefaultTimeout(1 * time.Minute),
+		},
+	}
+}
+
+func resourceLabelCreate(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	var existing []Label
+	err := awx.apiList("/api/v2/labels/", map[string]string{
+		"name":         d.Get("name").(string),
+		"organization": strconv.Itoa(d.Get("organization_id").(int)),
+	}, &existing)
+	if err != nil {
+		return err
+	}
+	if len(existing) >= 1 {
+		return fmt.Errorf("Label with name %s already exists in organization %d",
+			d.Get("name").(string), d.Get("organization_id").(int))
+	}
+
+	result := new(Label)
+	if err := awx.apiPost("/api/v2/labels/", labelPayload(d), result); err != nil {
+		return err
+	}
+
+	d.SetId(strconv.Itoa(result.ID))
+	return resourceLabelRead(d, m)
+}
+
+func resourceLabelUpdate(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	if err := awx.apiPatch(fmt.Sprintf("/api/v2/labels/%s/", d.Id()), labelPayload(d), nil); err != nil {
+		return err
+	}
+	return resourceLabelRead(d, m)
+}
+
+func resourceLabelRead(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	result := new(Label)
+	if err := awx.apiGet(fmt.Sprintf("/api/v2/labels/%s/", d.Id()), result, nil); err != nil {
+		// AWX deletes a label once it is detached from its last template
+		if isNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	d = setLabelResourceData(d, result)
+	return nil
+}
+
+func resourceLabelDelete(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	err := awx.apiDelete(fmt.Sprintf("/api/v2/labels/%s/", d.Id()))
+	if e, ok := err.(*APIError); ok && e.StatusCode == http.StatusMethodNotAllowed {
+		// Labels cannot be deleted through the API, AWX removes them once they are orphaned
+		log.Printf("[WARN] Label %s cannot be deleted, it will be removed by AWX once orphaned", d.Id())
+		err = nil
+	}
+	if err != nil && !isNotFound(err) {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
+
+func labelPayload(d *schema.ResourceData) map[string]interface{} {
+	return map[string]interface{}{
+		"name":         d.Get("name").(string),
+		"organization": d.Get("organization_id").(int),
+	}
+}
+
+func setLabelResourceData(d *schema.ResourceData, r *Label) *schema.ResourceData {
+	d.Set("name", r.Name)
+	d.Set("organization_id", r.Organization)
+	return d
+}
@@ -0,0 +1,76 @@
+package awx
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// awx_label test case
+func TestAccAWXLabel(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { TestAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLabelConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckStateLabel("awx_label.testacc-label_1", "name", "testacc-label_1"),
+					testAccCheckStateLabel("awx_label.testacc-label_1", "organization_id", "1"),
+					testAccCheckStateLabel("awx_job_template.testacc-jt_1", "label_ids.#", "2"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckStateLabel(name, skey, svalue string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("%s not found", name)
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		cr := rs.Primary
+
+		if cr.Attributes[skey] != svalue {
+			return fmt.Errorf("%s != %s (actual: %s)", skey, svalue, cr.Attributes[skey])
+		}
+
+		return nil
+	}
+}
+
+const testAccLabelConfig = `
+resource "awx_project" "testacc-prj_1" {
+	name = "testacc-prj_1"
+	description = "AWX Acc test project"
+	scm_type = "git"
+	scm_url = "https://github.com/ansible/ansible-tower-samples"
+	organization_id = "1"
+}
+
+resource "awx_label" "testacc-label_1" {
+	name            = "testacc-label_1"
+	organization_id = 1
+}
+
+resource "awx_label" "testacc-label_2" {
+	name            = "testacc-label_2"
+	organization_id = 1
+}
+
+resource "awx_job_template" "testacc-jt_1" {
+	name         = "testacc-jt_1"
+	project_id   = "${awx_project.testacc-prj_1.id}"
+	job_type     = "run"
+	inventory_id = "1"
+	playbook     = "hello_world.yml"
+	label_ids    = ["${awx_label.testacc-label_1.id}", "${awx_label.testacc-label_2.id}"]
+}
+`
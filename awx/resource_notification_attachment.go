@@ -0,0 +1,152 @@
+package awx
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+// Endpoints of the objects notification templates can be attached to
+var notificationAttachmentEndpoints = map[string]string{
+	"job_template":          "job_templates",
+	"workflow_job_template": "workflow_job_templates",
+	"project":               "projects",
+	"inventory_source":      "inventory_sources",
+	"organization":          "organizations",
+}
+
+var notificationAttachmentEvents = []string{"started", "success", "error", "approvals"}
+
+func resourceNotificationAttachmentObject() *schema.Resource {
+	return &schema.Resource{
+		Create:        resourceNotificationAttachmentCreate,
+		Read:          resourceNotificationAttachmentRead,
+		Delete:        resourceNotificationAttachmentDelete,
+		CustomizeDiff: resourceNotificationAttachmentCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"notification_template_id": &schema.Schema{
+				Type:        schema.TypeInt,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Numeric ID of the notification template to attach.",
+			},
+			"resource_type": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"job_template", "workflow_job_template", "project", "inventory_source", "organization"}, false),
+				Description:  "One of: job_template, workflow_job_template, project, inventory_source, organization",
+			},
+			"resource_id": &schema.Schema{
+				Type:        schema.TypeInt,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Numeric ID of the object to attach the notification template to.",
+			},
+			"event": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice(notificationAttachmentEvents, false),
+				Description:  "One of: started, success, error, approvals (approvals only for workflow_job_template and organization)",
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			State: importNotificationAttachmentData,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(1 * time.Minute),
+			Delete: schema.DefaultTimeout(1 * time.Minute),
+		},
+	}
+}
+
+func resourceNotificationAttachmentCreate(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	resourceType := d.Get("resource_type").(string)
+	event := d.Get("event").(string)
+	endpoint := notificationAttachmentEndpoint(resourceType, d.Get("resource_id").(int), event)
+	if err := awx.apiAssociate(endpoint, d.Get("notification_template_id").(int)); err != nil {
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("%s:%d:%s:%d", resourceType, d.Get("resource_id").(int), event, d.Get("notification_template_id").(int)))
+	return resourceNotificationAttachmentRead(d, m)
+}
+
+func resourceNotificationAttachmentRead(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	endpoint := notificationAttachmentEndpoint(d.Get("resource_type").(string), d.Get("resource_id").(int), d.Get("event").(string))
+	var attached []NotificationTemplate
+	err := awx.apiList(endpoint, map[string]string{"id": strconv.Itoa(d.Get("notification_template_id").(int))}, &attached)
+	if err != nil {
+		if isNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	// Detached out of band (or the notification template is gone)
+	if len(attached) == 0 {
+		d.SetId("")
+	}
+	return nil
+}
+
+func resourceNotificationAttachmentDelete(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	endpoint := notificationAttachmentEndpoint(d.Get("resource_type").(string), d.Get("resource_id").(int), d.Get("event").(string))
+	if err := awx.apiDisassociate(endpoint, d.Get("notification_template_id").(int)); err != nil && !isNotFound(err) {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
+
+// resourceNotificationAttachmentCustomizeDiff reports approvals attached to objects without approvals at plan time.
+func resourceNotificationAttachmentCustomizeDiff(d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("resource_type") || !d.NewValueKnown("event") {
+		return nil
+	}
+	resourceType := d.Get("resource_type").(string)
+	if d.Get("event").(string) == "approvals" && resourceType != "workflow_job_template" && resourceType != "organization" {
+		return fmt.Errorf("Event approvals is only available on workflow_job_template and organization, not %s", resourceType)
+	}
+	return nil
+}
+
+func importNotificationAttachmentData(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.Split(d.Id(), ":")
+	if len(parts) != 4 {
+		return nil, fmt.Errorf("Expected an ID like <resource_type>:<resource_id>:<event>:<notification_template_id>, got %q", d.Id())
+	}
+	if _, ok := notificationAttachmentEndpoints[parts[0]]; !ok {
+		return nil, fmt.Errorf("Invalid resource type %q", parts[0])
+	}
+	if !stringInSlice(parts[2], notificationAttachmentEvents) {
+		return nil, fmt.Errorf("Invalid event %q", parts[2])
+	}
+	resourceID, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("Invalid resource ID %q", parts[1])
+	}
+	templateID, err := strconv.Atoi(parts[3])
+	if err != nil {
+		return nil, fmt.Errorf("Invalid notification template ID %q", parts[3])
+	}
+	d.Set("resource_type", parts[0])
+	d.Set("resource_id", resourceID)
+	d.Set("event", parts[2])
+	d.Set("notification_template_id", templateID)
+	return []*schema.ResourceData{d}, nil
+}
+
+func notificationAttachmentEndpoint(resourceType string, resourceID int, event string) string {
+	return fmt.Sprintf("/api/v2/%s/%d/notification_templates_%s/", notificationAttachmentEndpoints[resourceType], resourceID, event)
+}
@@ -0,0 +1,104 @@
+package awx
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// awx_notification_attachment test case
+func TestAccAWXNotificationAttachment(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { TestAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccNotificationAttachmentConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckStateNotificationAttachment("resource_type", "organization"),
+					testAccCheckStateNotificationAttachment("resource_id", "1"),
+					testAccCheckStateNotificationAttachment("event", "error"),
+				),
+			},
+			{
+				ResourceName:      "awx_notification_attachment.testacc-attachment_1",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+// awx_notification_attachment plan checks of the events, nothing is sent to AWX
+func TestAWXNotificationAttachmentApprovals(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config:      fmt.Sprintf(testAWXNotificationAttachmentApprovalsConfig, "job_template"),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("Event approvals is only available on workflow_job_template and organization, not job_template"),
+			},
+			{
+				Config:             fmt.Sprintf(testAWXNotificationAttachmentApprovalsConfig, "workflow_job_template"),
+				PlanOnly:           true,
+				ExpectNonEmptyPlan: true,
+			},
+		},
+	})
+}
+
+func testAccCheckStateNotificationAttachment(skey, svalue string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources["awx_notification_attachment.testacc-attachment_1"]
+		if !ok {
+			return fmt.Errorf("awx_notification_attachment.testacc-attachment_1 not found")
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		cr := rs.Primary
+
+		if cr.Attributes[skey] != svalue {
+			return fmt.Errorf("%s != %s (actual: %s)", skey, svalue, cr.Attributes[skey])
+		}
+
+		return nil
+	}
+}
+
+const testAccNotificationAttachmentConfig = `
+resource "awx_notification_template" "testacc-notification_1" {
+	name              = "testacc-notification_1"
+	organization_id   = 1
+	notification_type = "webhook"
+
+	webhook {
+		url = "https://hooks.example.com/awx"
+	}
+}
+
+resource "awx_notification_attachment" "testacc-attachment_1" {
+	notification_template_id = "${awx_notification_template.testacc-notification_1.id}"
+	resource_type            = "organization"
+	resource_id              = 1
+	event                    = "error"
+}
+`
+
+const testAWXNotificationAttachmentApprovalsConfig = `
+provider "awx" {
+	endpoint = "http://127.0.0.1:1"
+}
+
+resource "awx_notification_attachment" "approvals" {
+	notification_template_id = 1
+	resource_type            = %q
+	resource_id              = 1
+	event                    = "approvals"
+}
+`
@@ -0,0 +1,464 @@
+package awx
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+// encryptedValue is what AWX returns in place of secrets.
+const encryptedValue = "$encrypted$"
+
+// notificationField describes a key of the notification_configuration of a notification type.
+type notificationField struct {
+	name        string
+	apiName     string
+	kind        schema.ValueType
+	required    bool
+	sensitive   bool
+	def         interface{}
+	description string
+}
+
+var notificationFields = map[string][]notificationField{
+	"email": {
+		{name: "host", kind: schema.TypeString, required: true, description: "SMTP server host."},
+		{name: "port", kind: schema.TypeInt, required: true, description: "SMTP server port."},
+		{name: "username", kind: schema.TypeString, def: "", description: "SMTP username."},
+		{name: "password", kind: schema.TypeString, sensitive: true, def: "", description: "SMTP password."},
+		{name: "use_tls", kind: schema.TypeBool, def: false, description: "Use STARTTLS."},
+		{name: "use_ssl", kind: schema.TypeBool, def: false, description: "Use SSL."},
+		{name: "sender", kind: schema.TypeString, required: true, description: "Sender email address."},
+		{name: "recipients", kind: schema.TypeList, required: true, description: "Recipient email addresses."},
+		{name: "timeout", kind: schema.TypeInt, def: 30, description: "Timeout (in seconds) of the SMTP connection (1-120)."},
+	},
+	"slack": {
+		{name: "channels", kind: schema.TypeList, required: true, description: "Destination channels (e.g. #engineering)."},
+		{name: "token", kind: schema.TypeString, required: true, sensitive: true, description: "Slack bot token."},
+		{name: "hex_color", kind: schema.TypeString, def: "", description: "Notification color (e.g. #3af or #789abc)."},
+	},
+	"twilio": {
+		{name: "account_sid", kind: schema.TypeString, required: true, description: "Account SID."},
+		{name: "account_token", kind: schema.TypeString, required: true, sensitive: true, description: "Account token."},
+		{name: "from_number", kind: schema.TypeString, required: true, description: "Source phone number."},
+		{name: "to_numbers", kind: schema.TypeList, required: true, description: "Destination phone numbers."},
+	},
+	"pagerduty": {
+		{name: "subdomain", kind: schema.TypeString, required: true, description: "PagerDuty subdomain."},
+		{name: "token", kind: schema.TypeString, required: true, sensitive: true, description: "API token."},
+		{name: "service_key", kind: schema.TypeString, required: true, description: "API service/integration key."},
+		{name: "client_name", kind: schema.TypeString, required: true, description: "Client identifier."},
+	},
+	"grafana": {
+		{name: "grafana_url", kind: schema.TypeString, required: true, description: "Grafana URL."},
+		{name: "grafana_key", kind: schema.TypeString, required: true, sensitive: true, description: "Grafana API key."},
+		{name: "dashboard_id", apiName: "dashboardId", kind: schema.TypeInt, def: 0, description: "ID of the dashboard to annotate."},
+		{name: "panel_id", apiName: "panelId", kind: schema.TypeInt, def: 0, description: "ID of the panel to annotate."},
+		{name: "annotation_tags", kind: schema.TypeList, description: "Tags for the annotation."},
+		{name: "grafana_no_verify_ssl", kind: schema.TypeBool, def: false, description: "Disable SSL verification."},
+	},
+	"webhook": {
+		{name: "url", kind: schema.TypeString, required: true, description: "Target URL."},
+		{name: "http_method", kind: schema.TypeString, def: "POST", description: "One of: POST, PUT"},
+		{name: "username", kind: schema.TypeString, def: "", description: "Basic auth username."},
+		{name: "password", kind: schema.TypeString, sensitive: true, def: "", description: "Basic auth password."},
+		{name: "disable_ssl_verification", kind: schema.TypeBool, def: false, description: "Disable SSL verification."},
+		{name: "headers", kind: schema.TypeMap, description: "HTTP headers sent with the request."},
+	},
+	"mattermost": {
+		{name: "mattermost_url", kind: schema.TypeString, required: true, description: "Incoming webhook URL."},
+		{name: "mattermost_username", kind: schema.TypeString, def: "", description: "Username of the poster."},
+		{name: "mattermost_channel", kind: schema.TypeString, def: "", description: "Destination channel."},
+		{name: "mattermost_icon_url", kind: schema.TypeString, def: "", description: "Icon of the poster."},
+		{name: "mattermost_no_verify_ssl", kind: schema.TypeBool, def: false, description: "Disable SSL verification."},
+	},
+	"rocketchat": {
+		{name: "rocketchat_url", kind: schema.TypeString, required: true, description: "Incoming webhook URL."},
+		{name: "rocketchat_username", kind: schema.TypeString, def: "", description: "Username of the poster."},
+		{name: "rocketchat_icon_url", kind: schema.TypeString, def: "", description: "Icon of the poster."},
+		{name: "rocketchat_no_verify_ssl", kind: schema.TypeBool, def: false, description: "Disable SSL verification."},
+	},
+	"irc": {
+		{name: "server", kind: schema.TypeString, required: true, description: "IRC server address."},
+		{name: "port", kind: schema.TypeInt, required: true, description: "IRC server port."},
+		{name: "nickname", kind: schema.TypeString, required: true, description: "IRC nick."},
+		{name: "password", kind: schema.TypeString, sensitive: true, def: "", description: "IRC server password."},
+		{name: "use_ssl", kind: schema.TypeBool, def: false, description: "Use SSL."},
+		{name: "targets", kind: schema.TypeList, required: true, description: "Destination channels or users."},
+	},
+}
+
+var notificationMessageEvents = []string{"started", "success", "error"}
+
+var notificationApprovalEvents = []string{"running", "approved", "timed_out", "denied"}
+
+func resourceNotificationTemplateObject() *schema.Resource {
+	s := map[string]*schema.Schema{
+		"name": &schema.Schema{
+			Type:        schema.TypeString,
+			Required:    true,
+			Description: "Name of this notification template.",
+		},
+		"description": &schema.Schema{
+			Type:        schema.TypeString,
+			Optional:    true,
+			Default:     "",
+			Description: "Optional description of this notification template.",
+		},
+		"organization_id": &schema.Schema{
+			Type:        schema.TypeInt,
+			Required:    true,
+			Description: "Numeric ID of the notification template organization",
+		},
+		"notification_type": &schema.Schema{
+			Type:         schema.TypeString,
+			Required:     true,
+			ValidateFunc: validation.StringInSlice(notificationTypeNames(), false),
+			Description:  fmt.Sprintf("One of: %s. The block of the same name holds its configuration.", strings.Join(notificationTypeNames(), ", ")),
+		},
+		"messages": &schema.Schema{
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Description: "Custom messages, AWX default messages are used for the events without one.",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"started": notificationMessageSchema(),
+					"success": notificationMessageSchema(),
+					"error":   notificationMessageSchema(),
+					"workflow_approval": &schema.Schema{
+						Type:     schema.TypeList,
+						Optional: true,
+						MaxItems: 1,
+						Elem: &schema.Resource{
+							Schema: map[string]*schema.Schema{
+								"running":   notificationMessageSchema(),
+								"approved":  notificationMessageSchema(),
+								"timed_out": notificationMessageSchema(),
+								"denied":    notificationMessageSchema(),
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	for notificationType, fields := range notificationFields {
+		s[notificationType] = notificationConfigurationSchema(notificationType, fields)
+	}
+
+	return &schema.Resource{
+		Create:        resourceNotificationTemplateCreate,
+		Read:          resourceNotificationTemplateRead,
+		Delete:        resourceNotificationTemplateDelete,
+		Update:        resourceNotificationTemplateUpdate,
+		CustomizeDiff: resourceNotificationTemplateCustomizeDiff,
+
+		Schema: s,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(1 * time.Minute),
+			Update: schema.DefaultTimeout(1 * time.Minute),
+			Delete: schema.DefaultTimeout(1 * time.Minute),
+		},
+	}
+}
+
+func notificationTypeNames() []string {
+	names := []string{}
+	for notificationType := range notificationFields {
+		names = append(names, notificationType)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func notificationConfigurationSchema(notificationType string, fields []notificationField) *schema.Schema {
+	s := map[string]*schema.Schema{}
+	for _, f := range fields {
+		field := &schema.Schema{
+			Type:        f.kind,
+			Required:    f.required,
+			Optional:    !f.required,
+			Sensitive:   f.sensitive,
+			Description: f.description,
+		}
+		switch f.kind {
+		case schema.TypeList, schema.TypeMap:
+			field.Elem = &schema.Schema{Type: schema.TypeString}
+		default:
+			field.Default = f.def
+		}
+		if f.name == "http_method" {
+			field.ValidateFunc = validation.StringInSlice([]string{"POST", "PUT"}, false)
+		}
+		if f.name == "timeout" {
+			field.ValidateFunc = validation.IntBetween(1, 120)
+		}
+		s[f.name] = field
+	}
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Optional:    true,
+		MaxItems:    1,
+		Description: fmt.Sprintf("Configuration of the %s notifications.", notificationType),
+		Elem:        &schema.Resource{Schema: s},
+	}
+}
+
+func notificationMessageSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"message": &schema.Schema{
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      "",
+					ValidateFunc: validateNotificationMessage,
+					Description:  "Single line message (Jinja2 template), used as subject for emails.",
+				},
+				"body": &schema.Schema{
+					Type:         schema.TypeString,
+					Optional:     true,
+					Default:      "",
+					ValidateFunc: validateNotificationBody,
+					Description:  "Message body (Jinja2 template), for email, webhook and pagerduty notifications.",
+				},
+			},
+		},
+	}
+}
+
+func resourceNotificationTemplateCreate(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	result := new(NotificationTemplate)
+	if err := awx.apiPost("/api/v2/notification_templates/", notificationTemplatePayload(d), result); err != nil {
+		return err
+	}
+
+	d.SetId(strconv.Itoa(result.ID))
+	return resourceNotificationTemplateRead(d, m)
+}
+
+func resourceNotificationTemplateUpdate(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	endpoint := fmt.Sprintf("/api/v2/notification_templates/%s/", d.Id())
+	if err := awx.apiPatch(endpoint, notificationTemplatePayload(d), nil); err != nil {
+		return err
+	}
+	return resourceNotificationTemplateRead(d, m)
+}
+
+func resourceNotificationTemplateRead(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	result := new(NotificationTemplate)
+	if err := awx.apiGet(fmt.Sprintf("/api/v2/notification_templates/%s/", d.Id()), result, nil); err != nil {
+		if isNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	d = setNotificationTemplateResourceData(d, result)
+	return nil
+}
+
+func resourceNotificationTemplateDelete(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	if err := awx.apiDelete(fmt.Sprintf("/api/v2/notification_templates/%s/", d.Id())); err != nil && !isNotFound(err) {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
+
+func resourceNotificationTemplateCustomizeDiff(d *schema.ResourceDiff, m interface{}) error {
+	notificationType := d.Get("notification_type").(string)
+	if notificationType == "" {
+		return nil
+	}
+	for _, name := range notificationTypeNames() {
+		set := len(d.Get(name).([]interface{})) > 0
+		if name == notificationType && !set {
+			return fmt.Errorf("A %s block is required when notification_type is %q", name, notificationType)
+		}
+		if name != notificationType && set {
+			return fmt.Errorf("The %s block cannot be set when notification_type is %q", name, notificationType)
+		}
+	}
+	return nil
+}
+
+func notificationTemplatePayload(d *schema.ResourceData) map[string]interface{} {
+	notificationType := d.Get("notification_type").(string)
+	configuration := map[string]interface{}{}
+	if blocks := d.Get(notificationType).([]interface{}); len(blocks) > 0 && blocks[0] != nil {
+		block := blocks[0].(map[string]interface{})
+		for _, f := range notificationFields[notificationType] {
+			configuration[f.apiKey()] = block[f.name]
+		}
+	}
+
+	var messages interface{}
+	if blocks := d.Get("messages").([]interface{}); len(blocks) > 0 && blocks[0] != nil {
+		block := blocks[0].(map[string]interface{})
+		expanded := map[string]interface{}{}
+		for _, event := range notificationMessageEvents {
+			expanded[event] = expandNotificationMessage(block[event])
+		}
+		expanded["workflow_approval"] = nil
+		if approvals := block["workflow_approval"].([]interface{}); len(approvals) > 0 && approvals[0] != nil {
+			approval := approvals[0].(map[string]interface{})
+			expandedApproval := map[string]interface{}{}
+			for _, event := range notificationApprovalEvents {
+				expandedApproval[event] = expandNotificationMessage(approval[event])
+			}
+			expanded["workflow_approval"] = expandedApproval
+		}
+		messages = expanded
+	}
+
+	return map[string]interface{}{
+		"name":                       d.Get("name").(string),
+		"description":                d.Get("description").(string),
+		"organization":               d.Get("organization_id").(int),
+		"notification_type":          notificationType,
+		"notification_configuration": configuration,
+		"messages":                   messages,
+	}
+}
+
+func expandNotificationMessage(raw interface{}) interface{} {
+	blocks, ok := raw.([]interface{})
+	if !ok || len(blocks) == 0 || blocks[0] == nil {
+		return nil
+	}
+	block := blocks[0].(map[string]interface{})
+	return map[string]interface{}{
+		"message": block["message"].(string),
+		"body":    block["body"].(string),
+	}
+}
+
+func flattenNotificationMessage(raw interface{}) []interface{} {
+	message, ok := raw.(map[string]interface{})
+	if !ok {
+		return []interface{}{}
+	}
+	flat := map[string]interface{}{"message": "", "body": ""}
+	for _, k := range []string{"message", "body"} {
+		if v, ok := message[k].(string); ok {
+			flat[k] = v
+		}
+	}
+	return []interface{}{flat}
+}
+
+func (f notificationField) apiKey() string {
+	if f.apiName != "" {
+		return f.apiName
+	}
+	return f.name
+}
+
+// validateNotificationMessage checks the message is a single line template.
+func validateNotificationMessage(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+	if strings.ContainsAny(value, "\r\n") {
+		errors = append(errors, fmt.Errorf("%q: messages cannot contain newlines", k))
+	}
+	ws, bodyErrors := validateNotificationBody(v, k)
+	return ws, append(errors, bodyErrors...)
+}
+
+// validateNotificationBody checks the Jinja2 delimiters of the template are balanced.
+func validateNotificationBody(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+	closing := map[string]string{"{{": "}}", "{%": "%}", "{#": "#}"}
+	open := ""
+	for i := 0; i < len(value)-1; i++ {
+		token := value[i : i+2]
+		if open == "" {
+			if _, ok := closing[token]; ok {
+				open = token
+				i++
+			} else if token == "}}" || token == "%}" || token == "#}" {
+				errors = append(errors, fmt.Errorf("%q: unexpected %q at position %d", k, token, i))
+				return
+			}
+		} else if token == closing[open] {
+			open = ""
+			i++
+		}
+	}
+	if open != "" {
+		errors = append(errors, fmt.Errorf("%q: unclosed %q", k, open))
+	}
+	return
+}
+
+func setNotificationTemplateResourceData(d *schema.ResourceData, r *NotificationTemplate) *schema.ResourceData {
+	d.Set("name", r.Name)
+	d.Set("description", r.Description)
+	d.Set("organization_id", r.Organization)
+	d.Set("notification_type", r.NotificationType)
+
+	for _, notificationType := range notificationTypeNames() {
+		if notificationType != r.NotificationType {
+			d.Set(notificationType, []interface{}{})
+			continue
+		}
+		current := map[string]interface{}{}
+		if blocks := d.Get(notificationType).([]interface{}); len(blocks) > 0 && blocks[0] != nil {
+			current = blocks[0].(map[string]interface{})
+		}
+		block := map[string]interface{}{}
+		for _, f := range notificationFields[notificationType] {
+			value := r.NotificationConfiguration[f.apiKey()]
+			if f.sensitive && value == encryptedValue {
+				value = current[f.name]
+			}
+			if n, ok := value.(float64); ok {
+				value = int(n)
+			}
+			if value != nil {
+				block[f.name] = value
+			}
+		}
+		d.Set(notificationType, []interface{}{block})
+	}
+
+	// AWX may answer with every event set to null, which means no custom message at all
+	custom := false
+	messages := map[string]interface{}{}
+	for _, event := range notificationMessageEvents {
+		messages[event] = flattenNotificationMessage(r.Messages[event])
+		custom = custom || r.Messages[event] != nil
+	}
+	messages["workflow_approval"] = []interface{}{}
+	if approval, ok := r.Messages["workflow_approval"].(map[string]interface{}); ok {
+		flat := map[string]interface{}{}
+		for _, event := range notificationApprovalEvents {
+			flat[event] = flattenNotificationMessage(approval[event])
+		}
+		messages["workflow_approval"] = []interface{}{flat}
+		custom = true
+	}
+	if custom {
+		d.Set("messages", []interface{}{messages})
+	} else {
+		d.Set("messages", []interface{}{})
+	}
+	return d
+}
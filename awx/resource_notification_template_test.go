@@ -0,0 +1,86 @@
+package awx
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// awx_notification_template test case
+func TestAccAWXNotificationTemplate(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { TestAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccNotificationTemplateConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckStateNotificationTemplate("name", "testacc-notification_1"),
+					testAccCheckStateNotificationTemplate("notification_type", "slack"),
+					testAccCheckStateNotificationTemplate("slack.0.channels.0", "#ops"),
+					testAccCheckStateNotificationTemplate("slack.0.token", "xoxb-secret"),
+					testAccCheckStateNotificationTemplate("messages.0.error.0.message", "{{ job_friendly_name }} failed"),
+				),
+			},
+		},
+	})
+}
+
+func TestNotificationMessageValidation(t *testing.T) {
+	valid := []string{"", "{{ job.id }} {{ job.status }}", "{% if job.failed %}KO{% endif %}", "{# comment #}"}
+	for _, v := range valid {
+		if _, errs := validateNotificationMessage(v, "message"); len(errs) > 0 {
+			t.Errorf("%q: unexpected errors %v", v, errs)
+		}
+	}
+	invalid := []string{"line 1\nline 2", "{{ job.id }", "job.id }}", "{% if job.failed %}KO{% endif"}
+	for _, v := range invalid {
+		if _, errs := validateNotificationMessage(v, "message"); len(errs) == 0 {
+			t.Errorf("%q: expected an error", v)
+		}
+	}
+	if _, errs := validateNotificationBody("{\n  \"id\": {{ job.id }}\n}", "body"); len(errs) > 0 {
+		t.Errorf("multi-line body: unexpected errors %v", errs)
+	}
+}
+
+func testAccCheckStateNotificationTemplate(skey, svalue string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources["awx_notification_template.testacc-notification_1"]
+		if !ok {
+			return fmt.Errorf("awx_notification_template.testacc-notification_1 not found")
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		cr := rs.Primary
+
+		if cr.Attributes[skey] != svalue {
+			return fmt.Errorf("%s != %s (actual: %s)", skey, svalue, cr.Attributes[skey])
+		}
+
+		return nil
+	}
+}
+
+const testAccNotificationTemplateConfig = `
+resource "awx_notification_template" "testacc-notification_1" {
+	name              = "testacc-notification_1"
+	organization_id   = 1
+	notification_type = "slack"
+
+	slack {
+		channels = ["#ops"]
+		token    = "xoxb-secret"
+	}
+
+	messages {
+		error {
+			message = "{{ job_friendly_name }} failed"
+		}
+	}
+}
+`
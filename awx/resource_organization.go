@@ -33,7 +33,18 @@ func resourceOrganizationObject() *schema.Resource {
 				Type:        schema.TypeString,
 				Optional:    true,
 				Default:     "",
-				Description: "The path of the custom virtualenv.",
+				Description: "The path of the custom virtualenv (before AWX 18).",
+			},
+			"default_environment_id": &schema.Schema{
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Numeric ID of the fallback execution environment for the jobs of this organization (AWX 18 or later).",
+			},
+			"instance_group_ids": &schema.Schema{
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+				Description: "Ordered numeric IDs of the instance groups of this organization (authoritative once set, unset keeps the instance groups managed out of band, AWX falls back along this order).",
 			},
 		},
 		Importer: &schema.ResourceImporter{
@@ -49,7 +60,7 @@ func resourceOrganizationObject() *schema.Resource {
 }
 
 func resourceOrganizationCreate(d *schema.ResourceData, m interface{}) error {
-	awx := m.(*awxgo.AWX)
+	awx := m.(*Client)
 	awxService := awx.OrganizationService
 	_, res, err := awxService.ListOrganizations(map[string]string{
 		"name": d.Get("name").(string)})
@@ -61,21 +72,33 @@ func resourceOrganizationCreate(d *schema.ResourceData, m interface{}) error {
 			d.Get("name").(string))
 	}
 
-	result, err := awxService.CreateOrganization(map[string]interface{}{
+	payload := map[string]interface{}{
 		"name":              d.Get("name").(string),
 		"description":       d.Get("description").(string),
 		"custom_virtualenv": d.Get("custom_virtualenv").(string),
-	}, map[string]string{})
-	if err != nil {
+	}
+	if err := setExecutionEnvironmentPayload(awx, d, payload, "default_environment_id", "default_environment"); err != nil {
 		return err
 	}
 
+	result, err := awxService.CreateOrganization(payload, map[string]string{})
+	if err != nil {
+		return err
+	}
 	d.SetId(strconv.Itoa(result.ID))
+
+	if _, ok := d.GetOk("instance_group_ids"); ok {
+		endpoint := fmt.Sprintf("/api/v2/organizations/%d/instance_groups/", result.ID)
+		if err := awx.apiReconcileOrdered(endpoint, expandIDList(d.Get("instance_group_ids"))); err != nil {
+			return err
+		}
+	}
+
 	return resourceOrganizationRead(d, m)
 }
 
 func resourceOrganizationUpdate(d *schema.ResourceData, m interface{}) error {
-	awx := m.(*awxgo.AWX)
+	awx := m.(*Client)
 	awxService := awx.OrganizationService
 	_, res, err := awxService.ListOrganizations(map[string]string{
 		"id": d.Id()},
@@ -91,20 +114,32 @@ func resourceOrganizationUpdate(d *schema.ResourceData, m interface{}) error {
 	if err != nil {
 		return err
 	}
-	_, err = awxService.UpdateOrganization(id, map[string]interface{}{
+	payload := map[string]interface{}{
 		"name":              d.Get("name").(string),
 		"description":       d.Get("description").(string),
 		"custom_virtualenv": d.Get("custom_virtualenv").(string),
-	}, map[string]string{})
+	}
+	if err := setExecutionEnvironmentPayload(awx, d, payload, "default_environment_id", "default_environment"); err != nil {
+		return err
+	}
+
+	_, err = awxService.UpdateOrganization(id, payload, map[string]string{})
 	if err != nil {
 		return err
 	}
 
+	if d.HasChange("instance_group_ids") {
+		endpoint := fmt.Sprintf("/api/v2/organizations/%s/instance_groups/", d.Id())
+		if err := awx.apiReconcileOrdered(endpoint, expandIDList(d.Get("instance_group_ids"))); err != nil {
+			return err
+		}
+	}
+
 	return resourceOrganizationRead(d, m)
 }
 
 func resourceOrganizationRead(d *schema.ResourceData, m interface{}) error {
-	awx := m.(*awxgo.AWX)
+	awx := m.(*Client)
 	awxService := awx.OrganizationService
 	_, res, err := awxService.ListOrganizations(map[string]string{
 		"name": d.Get("name").(string)})
@@ -115,11 +150,17 @@ func resourceOrganizationRead(d *schema.ResourceData, m interface{}) error {
 		return nil
 	}
 	d = setOrganizationResourceData(d, res.Results[0])
-	return nil
+
+	if err := readRelatedIDs(awx, d, "instance_group_ids", fmt.Sprintf("/api/v2/organizations/%d/instance_groups/", res.Results[0].ID)); err != nil {
+		return err
+	}
+
+	endpoint := fmt.Sprintf("/api/v2/organizations/%d/", res.Results[0].ID)
+	return readExecutionEnvironment(awx, d, endpoint, "default_environment_id", "default_environment")
 }
 
 func resourceOrganizationDelete(d *schema.ResourceData, m interface{}) error {
-	awx := m.(*awxgo.AWX)
+	awx := m.(*Client)
 	awxService := awx.OrganizationService
 	id, err := strconv.Atoi(d.Id())
 	if err != nil {
@@ -0,0 +1,120 @@
+package awx
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+func resourceOrganizationMemberObject() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceOrganizationMemberCreate,
+		Read:   resourceOrganizationMemberRead,
+		Delete: resourceOrganizationMemberDelete,
+
+		Schema: map[string]*schema.Schema{
+			"organization_id": &schema.Schema{
+				Type:        schema.TypeInt,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Numeric ID of the organization.",
+			},
+			"user_id": &schema.Schema{
+				Type:        schema.TypeInt,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Numeric ID of the user.",
+			},
+			"role": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "member",
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice(organizationMemberRoles, false),
+				Description:  "One of: " + strings.Join(organizationMemberRoles, ", "),
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			State: importOrganizationMemberData,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(1 * time.Minute),
+			Delete: schema.DefaultTimeout(1 * time.Minute),
+		},
+	}
+}
+
+func resourceOrganizationMemberCreate(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	organizationID, userID, role := d.Get("organization_id").(int), d.Get("user_id").(int), d.Get("role").(string)
+	endpoint, err := organizationMembersEndpoint(awx, organizationID, role)
+	if err != nil {
+		return err
+	}
+	if err := awx.apiAssociate(endpoint, userID); err != nil {
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("%d:%s:%d", organizationID, role, userID))
+	return resourceOrganizationMemberRead(d, m)
+}
+
+func resourceOrganizationMemberRead(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	endpoint, err := organizationMembersEndpoint(awx, d.Get("organization_id").(int), d.Get("role").(string))
+	if err == nil {
+		var users []int
+		if users, err = awx.apiListIDs(endpoint); err == nil {
+			// Removed out of band
+			if !intInSlice(d.Get("user_id").(int), users) {
+				d.SetId("")
+			}
+			return nil
+		}
+	}
+	if isNotFound(err) {
+		d.SetId("")
+		return nil
+	}
+	return err
+}
+
+func resourceOrganizationMemberDelete(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	endpoint, err := organizationMembersEndpoint(awx, d.Get("organization_id").(int), d.Get("role").(string))
+	if err == nil {
+		err = awx.apiDisassociate(endpoint, d.Get("user_id").(int))
+	}
+	if err != nil && !isNotFound(err) {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
+
+func importOrganizationMemberData(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.Split(d.Id(), ":")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("Expected an ID like <organization_id>:<role>:<user_id>, got %q", d.Id())
+	}
+	organizationID, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("Invalid organization ID %q", parts[0])
+	}
+	if !stringInSlice(parts[1], organizationMemberRoles) {
+		return nil, fmt.Errorf("Invalid role %q, expected one of: %s", parts[1], strings.Join(organizationMemberRoles, ", "))
+	}
+	userID, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("Invalid user ID %q", parts[2])
+	}
+	d.Set("organization_id", organizationID)
+	d.Set("role", parts[1])
+	d.Set("user_id", userID)
+	return []*schema.ResourceData{d}, nil
+}
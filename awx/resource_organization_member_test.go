@@ -0,0 +1,64 @@
+package awx
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// awx_organization_member test case
+func TestAccAWXOrganizationMember(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { TestAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccOrganizationMemberConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckStateOrganizationMember("role", "admin"),
+				),
+			},
+			{
+				ResourceName:      "awx_organization_member.testacc-admin",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckStateOrganizationMember(skey, svalue string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources["awx_organization_member.testacc-admin"]
+		if !ok {
+			return fmt.Errorf("awx_organization_member.testacc-admin not found")
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		cr := rs.Primary
+
+		if cr.Attributes[skey] != svalue {
+			return fmt.Errorf("%s != %s (actual: %s)", skey, svalue, cr.Attributes[skey])
+		}
+
+		return nil
+	}
+}
+
+const testAccOrganizationMemberConfig = `
+resource "awx_user" "testacc-user_1" {
+	username = "testacc-user_1"
+	password = "password"
+	email    = "testacc-user_1@test.td"
+}
+
+resource "awx_organization_member" "testacc-admin" {
+	organization_id = 1
+	user_id         = "${awx_user.testacc-user_1.id}"
+	role            = "admin"
+}
+`
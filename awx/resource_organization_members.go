@@ -0,0 +1,144 @@
+package awx
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+var organizationMemberRoles = []string{"member", "admin", "auditor"}
+
+func resourceOrganizationMembersObject() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceOrganizationMembersUpdate,
+		Read:   resourceOrganizationMembersRead,
+		Delete: resourceOrganizationMembersDelete,
+		Update: resourceOrganizationMembersUpdate,
+
+		Schema: map[string]*schema.Schema{
+			"organization_id": &schema.Schema{
+				Type:        schema.TypeInt,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Numeric ID of the organization.",
+			},
+			"role": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "member",
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice(organizationMemberRoles, false),
+				Description:  "One of: " + strings.Join(organizationMemberRoles, ", "),
+			},
+			"user_ids": &schema.Schema{
+				Type:        schema.TypeSet,
+				Required:    true,
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+				Set:         schema.HashInt,
+				Description: "Numeric IDs of the users having the role in the organization (authoritative, other users lose the role).",
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			State: importOrganizationMembersData,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(1 * time.Minute),
+			Update: schema.DefaultTimeout(1 * time.Minute),
+			Delete: schema.DefaultTimeout(1 * time.Minute),
+		},
+	}
+}
+
+func resourceOrganizationMembersUpdate(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	organizationID, role := d.Get("organization_id").(int), d.Get("role").(string)
+	endpoint, err := organizationMembersEndpoint(awx, organizationID, role)
+	if err != nil {
+		return err
+	}
+	if err := awx.apiReconcile(endpoint, expandIDSet(d.Get("user_ids"))); err != nil {
+		return err
+	}
+
+	d.SetId(fmt.Sprintf("%d:%s", organizationID, role))
+	return resourceOrganizationMembersRead(d, m)
+}
+
+func resourceOrganizationMembersRead(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	endpoint, err := organizationMembersEndpoint(awx, d.Get("organization_id").(int), d.Get("role").(string))
+	if err == nil {
+		var users []int
+		if users, err = awx.apiListIDs(endpoint); err == nil {
+			d.Set("user_ids", users)
+			return nil
+		}
+	}
+	if isNotFound(err) {
+		d.SetId("")
+		return nil
+	}
+	return err
+}
+
+// resourceOrganizationMembersDelete removes the role from all the users.
+func resourceOrganizationMembersDelete(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	endpoint, err := organizationMembersEndpoint(awx, d.Get("organization_id").(int), d.Get("role").(string))
+	if err == nil {
+		err = awx.apiReconcile(endpoint, []int{})
+	}
+	if err != nil && !isNotFound(err) {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
+
+func importOrganizationMembersData(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.Split(d.Id(), ":")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("Expected an ID like <organization_id>:<role>, got %q", d.Id())
+	}
+	organizationID, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("Invalid organization ID %q", parts[0])
+	}
+	if !stringInSlice(parts[1], organizationMemberRoles) {
+		return nil, fmt.Errorf("Invalid role %q, expected one of: %s", parts[1], strings.Join(organizationMemberRoles, ", "))
+	}
+	d.Set("organization_id", organizationID)
+	d.Set("role", parts[1])
+	return []*schema.ResourceData{d}, nil
+}
+
+// organizationMembersEndpoint returns the endpoint listing the users having role in the organization.
+// Members and admins have their own endpoints, auditors are managed through the role.
+func organizationMembersEndpoint(awx *Client, organizationID int, role string) (string, error) {
+	switch role {
+	case "member":
+		return fmt.Sprintf("/api/v2/organizations/%d/users/", organizationID), nil
+	case "admin":
+		return fmt.Sprintf("/api/v2/organizations/%d/admins/", organizationID), nil
+	}
+	var organization struct {
+		SummaryFields struct {
+			ObjectRoles map[string]struct {
+				ID int `json:"id"`
+			} `json:"object_roles"`
+		} `json:"summary_fields"`
+	}
+	if err := awx.apiGet(fmt.Sprintf("/api/v2/organizations/%d/", organizationID), &organization, nil); err != nil {
+		return "", err
+	}
+	r, ok := organization.SummaryFields.ObjectRoles[role+"_role"]
+	if !ok {
+		return "", fmt.Errorf("Organization %d has no %s role", organizationID, role)
+	}
+	return fmt.Sprintf("/api/v2/roles/%d/users/", r.ID), nil
+}
@@ -0,0 +1,81 @@
+package awx
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// awx_organization_members test case
+func TestAccAWXOrganizationMembers(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { TestAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccOrganizationMembersConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckStateOrganizationMembers("awx_organization_members.testacc-members", "user_ids.#", "2"),
+					testAccCheckStateOrganizationMembers("awx_organization_members.testacc-auditors", "role", "auditor"),
+					testAccCheckStateOrganizationMembers("awx_organization_members.testacc-auditors", "user_ids.#", "1"),
+				),
+			},
+			{
+				ResourceName:      "awx_organization_members.testacc-auditors",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckStateOrganizationMembers(name, skey, svalue string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("%s not found", name)
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		cr := rs.Primary
+
+		if cr.Attributes[skey] != svalue {
+			return fmt.Errorf("%s != %s (actual: %s)", skey, svalue, cr.Attributes[skey])
+		}
+
+		return nil
+	}
+}
+
+const testAccOrganizationMembersConfig = `
+resource "awx_organization" "testacc-org_1" {
+	name = "testacc-org_1"
+}
+
+resource "awx_user" "testacc-user_1" {
+	username = "testacc-user_1"
+	password = "password"
+	email    = "testacc-user_1@test.td"
+}
+
+resource "awx_user" "testacc-user_2" {
+	username = "testacc-user_2"
+	password = "password"
+	email    = "testacc-user_2@test.td"
+}
+
+resource "awx_organization_members" "testacc-members" {
+	organization_id = "${awx_organization.testacc-org_1.id}"
+	user_ids        = ["${awx_user.testacc-user_1.id}", "${awx_user.testacc-user_2.id}"]
+}
+
+resource "awx_organization_members" "testacc-auditors" {
+	organization_id = "${awx_organization.testacc-org_1.id}"
+	role            = "auditor"
+	user_ids        = ["${awx_user.testacc-user_2.id}"]
+}
+`
@@ -102,7 +102,39 @@ func resourceProjectObject() *schema.Resource {
 				Type:        schema.TypeString,
 				Optional:    true,
 				Default:     nil,
-				Description: "Local absolute file path containing a custom Python virtualenv to use",
+				Description: "Local absolute file path containing a custom Python virtualenv to use (before AWX 18)",
+			},
+			"default_environment_id": &schema.Schema{
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Numeric ID of the execution environment to use for jobs inside of this project (AWX 18 or later).",
+			},
+			"wait_for_sync": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Wait for the initial update of the project when creating it, and fail if it does not succeed.",
+			},
+			"scm_revision": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The last revision fetched by a project update.",
+			},
+			"status": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Status of the project (e.g. successful, failed, never updated).",
+			},
+			"last_update_failed": &schema.Schema{
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the last update of the project failed.",
+			},
+			"playbooks": &schema.Schema{
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Playbooks available in the project.",
 			},
 		},
 		Importer: &schema.ResourceImporter{
@@ -110,7 +142,7 @@ func resourceProjectObject() *schema.Resource {
 		},
 
 		Timeouts: &schema.ResourceTimeout{
-			Create: schema.DefaultTimeout(1 * time.Minute),
+			Create: schema.DefaultTimeout(5 * time.Minute),
 			Update: schema.DefaultTimeout(1 * time.Minute),
 			Delete: schema.DefaultTimeout(5 * time.Minute),
 		},
@@ -118,7 +150,7 @@ func resourceProjectObject() *schema.Resource {
 }
 
 func resourceProjectCreate(d *schema.ResourceData, m interface{}) error {
-	awx := m.(*awxgo.AWX)
+	awx := m.(*Client)
 	awxService := awx.ProjectService
 
 	_, res, err := awxService.ListProjects(map[string]string{
@@ -133,7 +165,7 @@ func resourceProjectCreate(d *schema.ResourceData, m interface{}) error {
 			d.Get("name").(string), d.Get("organization_id").(int))
 	}
 
-	result, err := awxService.CreateProject(map[string]interface{}{
+	payload := map[string]interface{}{
 		"name":                     d.Get("name").(string),
 		"description":              d.Get("description").(string),
 		"scm_type":                 d.Get("scm_type").(string),
@@ -149,17 +181,27 @@ func resourceProjectCreate(d *schema.ResourceData, m interface{}) error {
 		"scm_update_cache_timeout": d.Get("scm_update_cache_timeout").(int),
 		"allow_override":           d.Get("allow_override").(bool),
 		"custom_virtualenv":        d.Get("custom_virtualenv").(string),
-	}, map[string]string{})
+	}
+	if err := setExecutionEnvironmentPayload(awx, d, payload, "default_environment_id", "default_environment"); err != nil {
+		return err
+	}
+
+	result, err := awxService.CreateProject(payload, map[string]string{})
 	if err != nil {
 		return err
 	}
 
 	d.SetId(strconv.Itoa(result.ID))
+	if d.Get("wait_for_sync").(bool) && result.ScmType != "" {
+		if err := waitForProjectSync(awx, result.ID, d.Timeout(schema.TimeoutCreate)); err != nil {
+			return err
+		}
+	}
 	return resourceProjectRead(d, m)
 }
 
 func resourceProjectUpdate(d *schema.ResourceData, m interface{}) error {
-	awx := m.(*awxgo.AWX)
+	awx := m.(*Client)
 	awxService := awx.ProjectService
 	_, res, err := awxService.ListProjects(map[string]string{
 		"id":           d.Id(),
@@ -176,7 +218,7 @@ func resourceProjectUpdate(d *schema.ResourceData, m interface{}) error {
 	if err != nil {
 		return err
 	}
-	_, err = awxService.UpdateProject(id, map[string]interface{}{
+	payload := map[string]interface{}{
 		"name":                     d.Get("name").(string),
 		"description":              d.Get("description").(string),
 		"scm_type":                 d.Get("scm_type").(string),
@@ -192,8 +234,12 @@ func resourceProjectUpdate(d *schema.ResourceData, m interface{}) error {
 		"scm_update_cache_timeout": d.Get("scm_update_cache_timeout").(int),
 		"allow_override":           d.Get("allow_override").(bool),
 		"custom_virtualenv":        d.Get("custom_virtualenv").(string),
-	}, map[string]string{})
-	if err != nil {
+	}
+	if err := setExecutionEnvironmentPayload(awx, d, payload, "default_environment_id", "default_environment"); err != nil {
+		return err
+	}
+
+	if _, err = awxService.UpdateProject(id, payload, map[string]string{}); err != nil {
 		return err
 	}
 
@@ -201,22 +247,31 @@ func resourceProjectUpdate(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceProjectRead(d *schema.ResourceData, m interface{}) error {
-	awx := m.(*awxgo.AWX)
+	awx := m.(*Client)
 	awxService := awx.ProjectService
 	_, res, err := awxService.ListProjects(map[string]string{
-		"name": d.Get("name").(string)})
+		"id": d.Id()})
 	if err != nil {
 		return err
 	}
 	if len(res.Results) == 0 {
+		d.SetId("")
 		return nil
 	}
 	d = setProjectResourceData(d, res.Results[0])
-	return nil
+
+	var playbooks []string
+	if err := awx.apiGet(fmt.Sprintf("/api/v2/projects/%s/playbooks/", d.Id()), &playbooks, nil); err != nil {
+		return err
+	}
+	d.Set("playbooks", playbooks)
+
+	endpoint := fmt.Sprintf("/api/v2/projects/%s/", d.Id())
+	return readExecutionEnvironment(awx, d, endpoint, "default_environment_id", "default_environment")
 }
 
 func resourceProjectDelete(d *schema.ResourceData, m interface{}) error {
-	awx := m.(*awxgo.AWX)
+	awx := m.(*Client)
 	awxService := awx.ProjectService
 	id, err := strconv.Atoi(d.Id())
 	if err != nil {
@@ -260,6 +315,55 @@ func resourceProjectDelete(d *schema.ResourceData, m interface{}) error {
 	return nil
 }
 
+// waitForProjectSync waits for the update AWX launches when the project is created and reports its output if it fails.
+func waitForProjectSync(awx *Client, projectID int, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	var jobID int
+	for jobID == 0 {
+		_, res, err := awx.ProjectService.ListProjects(map[string]string{"id": strconv.Itoa(projectID)})
+		if err != nil {
+			return err
+		}
+		if len(res.Results) == 1 && res.Results[0].SummaryFields != nil {
+			if id, ok := res.Results[0].SummaryFields.CurrentJob["id"].(float64); ok {
+				jobID = int(id)
+			} else if id, ok := res.Results[0].SummaryFields.LastJob["id"].(float64); ok {
+				jobID = int(id)
+			}
+		}
+		if jobID == 0 {
+			if time.Now().After(deadline) {
+				return fmt.Errorf("Timeout while waiting for the update of project %d to start", projectID)
+			}
+			time.Sleep(1 * time.Second)
+		}
+	}
+
+	for {
+		update, err := awx.ProjectUpdatesService.ProjectUpdateGet(jobID)
+		if err != nil {
+			return err
+		}
+		if !update.Finished.IsZero() {
+			if update.Status == "successful" {
+				return nil
+			}
+			var stdout struct {
+				Content string `json:"content"`
+			}
+			endpoint := fmt.Sprintf("/api/v2/project_updates/%d/stdout/", jobID)
+			if err := awx.apiGet(endpoint, &stdout, map[string]string{"format": "json"}); err != nil {
+				return fmt.Errorf("Update %d of project %d %s: %s", jobID, projectID, update.Status, update.JobExplanation)
+			}
+			return fmt.Errorf("Update %d of project %d %s: %s\n%s", jobID, projectID, update.Status, update.JobExplanation, stdout.Content)
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("Timeout while waiting for update %d of project %d", jobID, projectID)
+		}
+		time.Sleep(1 * time.Second)
+	}
+}
+
 func setProjectResourceData(d *schema.ResourceData, r *awxgo.Project) *schema.ResourceData {
 	d.Set("name", r.Name)
 	d.Set("description", r.Description)
@@ -276,5 +380,8 @@ func setProjectResourceData(d *schema.ResourceData, r *awxgo.Project) *schema.Re
 	d.Set("scm_update_cache_timeout", r.ScmUpdateCacheTimeout)
 	d.Set("allow_override", r.AllowOverride)
 	d.Set("custom_virtualenv", r.CustomVirtualenv)
+	d.Set("scm_revision", r.ScmRevision)
+	d.Set("status", r.Status)
+	d.Set("last_update_failed", r.LastUpdateFailed)
 	return d
 }
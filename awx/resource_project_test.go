@@ -23,6 +23,10 @@ func TestAccAWXProject(t *testing.T) {
 					testAccCheckStateProject("scm_type", "git"),
 					testAccCheckStateProject("scm_update_on_launch", "true"),
 					testAccCheckStateProject("scm_url", "https://github.com/ansible/ansible-tower-samples"),
+					testAccCheckStateProject("status", "successful"),
+					testAccCheckStateProject("last_update_failed", "false"),
+					testAccCheckStateProject("playbooks.#", "1"),
+					testAccCheckStateProject("playbooks.0", "hello_world.yml"),
 				),
 			},
 		},
@@ -57,5 +61,6 @@ resource "awx_project" "testacc-prj_1" {
 	scm_url = "https://github.com/ansible/ansible-tower-samples"
 	scm_update_on_launch = true
 	organization_id = "1"
+	wait_for_sync = true
   }
 `
@@ -73,7 +73,7 @@ func resourceTeamRoleObject() *schema.Resource {
 }
 
 func resourceTeamRoleGrant(d *schema.ResourceData, m interface{}) error {
-	awx := m.(*awxgo.AWX)
+	awx := m.(*Client)
 	awxService := awx.TeamService
 	_, res, err := awxService.ListTeams(map[string]string{
 		"id": d.Get("team_id").(string)},
@@ -101,7 +101,7 @@ func resourceTeamRoleGrant(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceTeamRoleRevoke(d *schema.ResourceData, m interface{}) error {
-	awx := m.(*awxgo.AWX)
+	awx := m.(*Client)
 	awxService := awx.TeamService
 
 	_, res, err := awxService.ListTeams(map[string]string{
@@ -129,7 +129,7 @@ func resourceTeamRoleRevoke(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceTeamRoleRead(d *schema.ResourceData, m interface{}) error {
-	awx := m.(*awxgo.AWX)
+	awx := m.(*Client)
 	awxService := awx.TeamService
 	_, res, err := awxService.ListTeams(map[string]string{
 		"id": d.Get("team_id").(string)})
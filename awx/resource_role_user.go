@@ -73,7 +73,7 @@ func resourceUserRoleObject() *schema.Resource {
 }
 
 func resourceUserRoleGrant(d *schema.ResourceData, m interface{}) error {
-	awx := m.(*awxgo.AWX)
+	awx := m.(*Client)
 	awxService := awx.UserService
 	_, res, err := awxService.ListUsers(map[string]string{
 		"id": d.Get("user_id").(string)},
@@ -101,7 +101,7 @@ func resourceUserRoleGrant(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceUserRoleRevoke(d *schema.ResourceData, m interface{}) error {
-	awx := m.(*awxgo.AWX)
+	awx := m.(*Client)
 	awxService := awx.UserService
 
 	_, res, err := awxService.ListUsers(map[string]string{
@@ -130,7 +130,7 @@ func resourceUserRoleRevoke(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceUserRoleRead(d *schema.ResourceData, m interface{}) error {
-	awx := m.(*awxgo.AWX)
+	awx := m.(*Client)
 	awxService := awx.UserService
 	_, res, err := awxService.ListUsers(map[string]string{
 		"id": d.Get("user_id").(string)})
@@ -0,0 +1,452 @@
+package awx
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+var scheduleStructuredFields = []string{"start", "timezone", "frequency", "interval", "by_day", "until", "occurrence_count"}
+
+func resourceScheduleObject() *schema.Resource {
+	return &schema.Resource{
+		Create:        resourceScheduleCreate,
+		Read:          resourceScheduleRead,
+		Delete:        resourceScheduleDelete,
+		Update:        resourceScheduleUpdate,
+		CustomizeDiff: resourceScheduleCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"name": &schema.Schema{
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of this schedule.",
+			},
+			"description": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: "Optional description of this schedule.",
+			},
+			"unified_job_template_id": &schema.Schema{
+				Type:        schema.TypeInt,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Numeric ID of the job template, workflow job template, project or inventory source to launch.",
+			},
+			"enabled": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Enables processing of this schedule.",
+			},
+			"rrule": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: scheduleStructuredFields,
+				ValidateFunc:  validateRRule,
+				Description:   "A value representing the schedules iCal recurrence rule (e.g. DTSTART;TZID=Europe/Zurich:20200101T020000 RRULE:FREQ=DAILY;INTERVAL=1). Computed when the structured fields are used.",
+			},
+			"start": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"rrule"},
+				Description:   "First occurrence, RFC 3339 or YYYY-MM-DDTHH:MM:SS local to timezone.",
+			},
+			"timezone": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"rrule"},
+				ValidateFunc:  validateTimezone,
+				Description:   "IANA timezone of start (defaults to UTC).",
+			},
+			"frequency": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"rrule"},
+				ValidateFunc:  validation.StringInSlice(rruleFrequencies, false),
+				Description:   "One of: MINUTELY, HOURLY, DAILY, WEEKLY, MONTHLY, YEARLY",
+			},
+			"interval": &schema.Schema{
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Default:       1,
+				ConflictsWith: []string{"rrule"},
+				ValidateFunc:  validation.IntAtLeast(1),
+				Description:   "Run every interval frequency units.",
+			},
+			"by_day": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringInSlice([]string{"MO", "TU", "WE", "TH", "FR", "SA", "SU"}, false),
+				},
+				ConflictsWith: []string{"rrule"},
+				Description:   "Restrict occurrences to these week days (MO, TU, WE, TH, FR, SA, SU).",
+			},
+			"until": &schema.Schema{
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"rrule", "occurrence_count"},
+				Description:   "Last possible occurrence, RFC 3339 or YYYY-MM-DDTHH:MM:SS local to timezone.",
+			},
+			"occurrence_count": &schema.Schema{
+				Type:          schema.TypeInt,
+				Optional:      true,
+				ConflictsWith: []string{"rrule", "until"},
+				ValidateFunc:  validation.IntBetween(1, 999),
+				Description:   "Number of occurrences before the schedule ends.",
+			},
+			"extra_data": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "",
+				ValidateFunc: validation.ValidateJsonString,
+				StateFunc:    normalizeJSON,
+				Description:  "JSON encoded extra variables passed to the launched job.",
+			},
+			"inventory_id": &schema.Schema{
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Inventory applied as a prompt, if the template prompts for it.",
+			},
+			"scm_branch": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Branch applied as a prompt, if the template prompts for it.",
+			},
+			"job_type": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"run", "check"}, false),
+				Description:  "Job type applied as a prompt, if the template prompts for it.",
+			},
+			"job_tags": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Job tags applied as a prompt, if the template prompts for it.",
+			},
+			"skip_tags": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Skip tags applied as a prompt, if the template prompts for it.",
+			},
+			"limit": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Limit applied as a prompt, if the template prompts for it.",
+			},
+			"diff_mode": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Diff mode applied as a prompt, if the template prompts for it.",
+			},
+			"verbosity": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntBetween(0, 5),
+				Description:  "Verbosity applied as a prompt, if the template prompts for it.",
+			},
+
+			// Offline preview
+			"preview_count": &schema.Schema{
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      5,
+				ValidateFunc: validation.IntBetween(0, 100),
+				Description:  "Number of occurrences to compute in next_occurrences.",
+			},
+			"next_occurrences": &schema.Schema{
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "First occurrences (RFC 3339) from the start of the rrule, computed locally so they do not drift with the clock.",
+			},
+			"next_run": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The next time the schedule will run, as reported by AWX.",
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(1 * time.Minute),
+			Update: schema.DefaultTimeout(1 * time.Minute),
+			Delete: schema.DefaultTimeout(1 * time.Minute),
+		},
+	}
+}
+
+func resourceScheduleCreate(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	payload, err := schedulePayload(d)
+	if err != nil {
+		return err
+	}
+	payload["unified_job_template"] = d.Get("unified_job_template_id").(int)
+	for _, k := range []string{"scm_branch", "job_type", "job_tags", "skip_tags", "limit"} {
+		if v, ok := d.GetOk(k); ok {
+			payload[k] = v.(string)
+		}
+	}
+	if v, ok := d.GetOk("inventory_id"); ok {
+		payload["inventory"] = v.(int)
+	}
+	for _, k := range []string{"diff_mode", "verbosity"} {
+		if v, ok := d.GetOkExists(k); ok {
+			payload[k] = v
+		}
+	}
+
+	result := new(Schedule)
+	if err := awx.apiPost("/api/v2/schedules/", payload, result); err != nil {
+		return err
+	}
+
+	d.SetId(strconv.Itoa(result.ID))
+	return resourceScheduleRead(d, m)
+}
+
+func resourceScheduleUpdate(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	payload, err := schedulePayload(d)
+	if err != nil {
+		return err
+	}
+	for _, k := range []string{"scm_branch", "job_type", "job_tags", "skip_tags", "limit"} {
+		if d.HasChange(k) {
+			payload[k] = d.Get(k).(string)
+		}
+	}
+	if d.HasChange("inventory_id") {
+		payload["inventory"] = nil
+		if v, ok := d.GetOk("inventory_id"); ok {
+			payload["inventory"] = v.(int)
+		}
+	}
+	for _, k := range []string{"diff_mode", "verbosity"} {
+		if d.HasChange(k) {
+			if v, ok := d.GetOkExists(k); ok {
+				payload[k] = v
+			} else {
+				payload[k] = nil
+			}
+		}
+	}
+
+	if err := awx.apiPatch(fmt.Sprintf("/api/v2/schedules/%s/", d.Id()), payload, nil); err != nil {
+		return err
+	}
+	return resourceScheduleRead(d, m)
+}
+
+func resourceScheduleRead(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	result := new(Schedule)
+	if err := awx.apiGet(fmt.Sprintf("/api/v2/schedules/%s/", d.Id()), result, nil); err != nil {
+		if isNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	d = setScheduleResourceData(d, result)
+	return nil
+}
+
+func resourceScheduleDelete(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	if err := awx.apiDelete(fmt.Sprintf("/api/v2/schedules/%s/", d.Id())); err != nil && !isNotFound(err) {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
+
+func resourceScheduleCustomizeDiff(d *schema.ResourceDiff, m interface{}) error {
+	if _, ok := d.GetOk("frequency"); ok || !d.NewValueKnown("frequency") {
+		for _, k := range scheduleStructuredFields {
+			if !d.NewValueKnown(k) {
+				if err := d.SetNewComputed("rrule"); err != nil {
+					return err
+				}
+				return d.SetNewComputed("next_occurrences")
+			}
+		}
+	} else if !d.NewValueKnown("rrule") {
+		return d.SetNewComputed("next_occurrences")
+	}
+
+	rule, err := scheduleRRule(d)
+	if err != nil {
+		return err
+	}
+	changed := d.Id() == "" || d.HasChange("rrule") || d.HasChange("preview_count")
+	if rule != d.Get("rrule").(string) {
+		if err := d.SetNew("rrule", rule); err != nil {
+			return err
+		}
+		changed = true
+	}
+	if changed {
+		next, err := scheduleOccurrences(rule, d.Get("preview_count").(int))
+		if err != nil {
+			return err
+		}
+		return d.SetNew("next_occurrences", next)
+	}
+	return nil
+}
+
+// schedulePayload returns the fields sent on both create and update.
+func schedulePayload(d *schema.ResourceData) (map[string]interface{}, error) {
+	rule, err := scheduleRRule(d)
+	if err != nil {
+		return nil, err
+	}
+	extraData := map[string]interface{}{}
+	if v := d.Get("extra_data").(string); v != "" {
+		if err := json.Unmarshal([]byte(v), &extraData); err != nil {
+			return nil, fmt.Errorf("Invalid extra_data: %s", err)
+		}
+	}
+	return map[string]interface{}{
+		"name":        d.Get("name").(string),
+		"description": d.Get("description").(string),
+		"enabled":     d.Get("enabled").(bool),
+		"rrule":       rule,
+		"extra_data":  extraData,
+	}, nil
+}
+
+// scheduleRRule returns the rrule configured either as a raw string or through the structured fields.
+func scheduleRRule(d resourceGetter) (string, error) {
+	frequency, ok := d.GetOk("frequency")
+	if !ok {
+		rule := d.Get("rrule").(string)
+		if rule == "" {
+			return "", fmt.Errorf("One of rrule or frequency (with start) must be set")
+		}
+		return rule, nil
+	}
+
+	loc := time.UTC
+	if tz, ok := d.GetOk("timezone"); ok {
+		var err error
+		if loc, err = loadTimezone(tz.(string)); err != nil {
+			return "", err
+		}
+	}
+	rawStart, ok := d.GetOk("start")
+	if !ok {
+		return "", fmt.Errorf("start is required when frequency is set")
+	}
+	start, err := parseScheduleTime(rawStart.(string), loc)
+	if err != nil {
+		return "", fmt.Errorf("start: %s", err)
+	}
+	var until time.Time
+	if rawUntil, ok := d.GetOk("until"); ok {
+		if until, err = parseScheduleTime(rawUntil.(string), loc); err != nil {
+			return "", fmt.Errorf("until: %s", err)
+		}
+	}
+	var byDay []string
+	for _, day := range d.Get("by_day").([]interface{}) {
+		byDay = append(byDay, day.(string))
+	}
+
+	rule := buildRRule(start, frequency.(string), d.Get("interval").(int), byDay, until, d.Get("occurrence_count").(int))
+	if _, err := parseRRule(rule); err != nil {
+		return "", err
+	}
+	return rule, nil
+}
+
+// scheduleOccurrences returns the first n occurrences of the rule from its DTSTART, the wall clock is left out so
+// that plans settle.
+func scheduleOccurrences(rule string, n int) ([]string, error) {
+	r, err := parseRRule(rule)
+	if err != nil {
+		return nil, err
+	}
+	next := []string{}
+	for _, t := range r.occurrences(r.dtstart.Add(-time.Nanosecond), n) {
+		next = append(next, t.Format(time.RFC3339))
+	}
+	return next, nil
+}
+
+func validateRRule(v interface{}, k string) (ws []string, errors []error) {
+	if _, err := parseRRule(v.(string)); err != nil {
+		errors = append(errors, fmt.Errorf("%q: %s", k, err))
+	}
+	return
+}
+
+func validateTimezone(v interface{}, k string) (ws []string, errors []error) {
+	if _, err := loadTimezone(v.(string)); err != nil {
+		errors = append(errors, fmt.Errorf("%q: %s", k, err))
+	}
+	return
+}
+
+func setScheduleResourceData(d *schema.ResourceData, r *Schedule) *schema.ResourceData {
+	d.Set("name", r.Name)
+	d.Set("description", r.Description)
+	d.Set("unified_job_template_id", r.UnifiedJobTemplate)
+	d.Set("enabled", r.Enabled)
+	d.Set("rrule", r.Rrule)
+	if len(r.ExtraData) > 0 {
+		b, _ := json.Marshal(r.ExtraData)
+		d.Set("extra_data", normalizeJSON(string(b)))
+	} else {
+		d.Set("extra_data", "")
+	}
+	if r.Inventory != nil {
+		d.Set("inventory_id", *r.Inventory)
+	} else {
+		d.Set("inventory_id", nil)
+	}
+	for k, v := range map[string]*string{
+		"scm_branch": r.ScmBranch,
+		"job_type":   r.JobType,
+		"job_tags":   r.JobTags,
+		"skip_tags":  r.SkipTags,
+		"limit":      r.Limit,
+	} {
+		if v != nil {
+			d.Set(k, *v)
+		} else {
+			d.Set(k, "")
+		}
+	}
+	if r.DiffMode != nil {
+		d.Set("diff_mode", *r.DiffMode)
+	} else {
+		d.Set("diff_mode", nil)
+	}
+	if r.Verbosity != nil {
+		d.Set("verbosity", *r.Verbosity)
+	} else {
+		d.Set("verbosity", nil)
+	}
+	if r.NextRun != nil {
+		d.Set("next_run", r.NextRun.Format(time.RFC3339))
+	} else {
+		d.Set("next_run", "")
+	}
+	if next, err := scheduleOccurrences(r.Rrule, d.Get("preview_count").(int)); err == nil {
+		d.Set("next_occurrences", next)
+	}
+	return d
+}
@@ -0,0 +1,83 @@
+package awx
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// awx_schedule test case
+func TestAccAWXSchedule(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { TestAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccScheduleConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckStateSchedule("name", "testacc-schedule_1"),
+					testAccCheckStateSchedule("rrule", "DTSTART;TZID=Europe/Zurich:20300101T020000 RRULE:FREQ=WEEKLY;INTERVAL=1;BYDAY=MO,FR"),
+					testAccCheckStateSchedule("next_occurrences.#", "3"),
+					testAccCheckStateSchedule("next_occurrences.0", "2030-01-04T02:00:00+01:00"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckStateSchedule(skey, svalue string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources["awx_schedule.testacc-schedule_1"]
+		if !ok {
+			return fmt.Errorf("awx_schedule.testacc-schedule_1 not found")
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		cr := rs.Primary
+
+		if cr.Attributes[skey] != svalue {
+			return fmt.Errorf("%s != %s (actual: %s)", skey, svalue, cr.Attributes[skey])
+		}
+
+		return nil
+	}
+}
+
+const testAccScheduleConfig = `
+resource "awx_project" "testacc-prj_1" {
+	name = "testacc-prj_1"
+	description = "AWX Acc test project"
+	scm_type = "git"
+	scm_url = "https://github.com/ansible/ansible-tower-samples"
+	organization_id = "1"
+}
+
+resource "awx_schedule" "testacc-schedule_1" {
+	name                    = "testacc-schedule_1"
+	unified_job_template_id = "${awx_project.testacc-prj_1.id}"
+	start                   = "2030-01-01T02:00:00"
+	timezone                = "Europe/Zurich"
+	frequency               = "WEEKLY"
+	by_day                  = ["MO", "FR"]
+	preview_count           = 3
+}
+`
+
+func TestScheduleOccurrences(t *testing.T) {
+	rule := "DTSTART;TZID=Europe/Zurich:20100101T020000 RRULE:FREQ=WEEKLY;INTERVAL=1;BYDAY=MO,FR"
+	expected := []string{"2010-01-01T02:00:00+01:00", "2010-01-04T02:00:00+01:00", "2010-01-08T02:00:00+01:00"}
+	for i := 0; i < 2; i++ {
+		next, err := scheduleOccurrences(rule, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Join(next, " ") != strings.Join(expected, " ") {
+			t.Errorf("got %v\nwant %v", next, expected)
+		}
+	}
+}
@@ -0,0 +1,216 @@
+package awx
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+var settingsCategories = []string{"jobs", "system", "ui", "authentication", "logging"}
+
+func resourceSettingsObject() *schema.Resource {
+	return &schema.Resource{
+		Create:        resourceSettingsCreate,
+		Read:          resourceSettingsRead,
+		Delete:        resourceSettingsDelete,
+		Update:        resourceSettingsUpdate,
+		CustomizeDiff: resourceSettingsCustomizeDiff,
+
+		Schema: map[string]*schema.Schema{
+			"category": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice(settingsCategories, false),
+				Description:  "One of: " + strings.Join(settingsCategories, ", "),
+			},
+			"settings": &schema.Schema{
+				Type:             schema.TypeMap,
+				Optional:         true,
+				Elem:             &schema.Schema{Type: schema.TypeString},
+				ValidateFunc:     validateJSONValues,
+				DiffSuppressFunc: suppressEquivalentJSONValue,
+				Description:      "Settings managed by this resource, values are JSON encoded (e.g. jsonencode(true)).",
+			},
+			"sensitive_settings": &schema.Schema{
+				Type:             schema.TypeMap,
+				Optional:         true,
+				Sensitive:        true,
+				Elem:             &schema.Schema{Type: schema.TypeString},
+				ValidateFunc:     validateJSONValues,
+				DiffSuppressFunc: suppressEquivalentJSONValue,
+				Description:      "Secret settings managed by this resource, values are JSON encoded. AWX never returns them, so drift cannot be detected.",
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			State: importSettingsData,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(1 * time.Minute),
+			Update: schema.DefaultTimeout(1 * time.Minute),
+			Delete: schema.DefaultTimeout(1 * time.Minute),
+		},
+	}
+}
+
+func resourceSettingsCreate(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	category := d.Get("category").(string)
+	if err := awx.apiPatch(settingsEndpoint(category), settingsPayload(d), nil); err != nil {
+		return err
+	}
+
+	d.SetId(category)
+	return resourceSettingsRead(d, m)
+}
+
+func resourceSettingsUpdate(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	category := d.Get("category").(string)
+	payload := settingsPayload(d)
+	if err := awx.apiPatch(settingsEndpoint(category), payload, nil); err != nil {
+		return err
+	}
+
+	// Settings no longer managed are given back to AWX
+	var removed []string
+	for _, k := range []string{"settings", "sensitive_settings"} {
+		old, _ := d.GetChange(k)
+		for key := range old.(map[string]interface{}) {
+			if _, ok := payload[key]; !ok {
+				removed = append(removed, key)
+			}
+		}
+	}
+	if err := awx.resetSettings(category, removed); err != nil {
+		return err
+	}
+
+	return resourceSettingsRead(d, m)
+}
+
+func resourceSettingsRead(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	var current map[string]interface{}
+	if err := awx.apiGet(settingsEndpoint(d.Id()), &current, nil); err != nil {
+		if isNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	d.Set("category", d.Id())
+	for _, k := range []string{"settings", "sensitive_settings"} {
+		values, err := flattenSettings(d.Get(k).(map[string]interface{}), current)
+		if err != nil {
+			return err
+		}
+		d.Set(k, values)
+	}
+	return nil
+}
+
+func resourceSettingsDelete(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	var keys []string
+	for key := range settingsPayload(d) {
+		keys = append(keys, key)
+	}
+	if err := awx.resetSettings(d.Id(), keys); err != nil {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
+
+func resourceSettingsCustomizeDiff(d *schema.ResourceDiff, m interface{}) error {
+	sensitive := d.Get("sensitive_settings").(map[string]interface{})
+	for key := range d.Get("settings").(map[string]interface{}) {
+		if _, ok := sensitive[key]; ok {
+			return fmt.Errorf("Setting %s cannot be both in settings and sensitive_settings", key)
+		}
+	}
+	return nil
+}
+
+// importSettingsData imports a category, the settings to manage have to be added to the configuration.
+func importSettingsData(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	if !stringInSlice(d.Id(), settingsCategories) {
+		return nil, fmt.Errorf("Invalid settings category %q, expected one of: %s", d.Id(), strings.Join(settingsCategories, ", "))
+	}
+	d.Set("category", d.Id())
+	return []*schema.ResourceData{d}, nil
+}
+
+func settingsEndpoint(category string) string {
+	return fmt.Sprintf("/api/v2/settings/%s/", category)
+}
+
+// settingsPayload decodes the managed settings, the values have been validated as JSON.
+func settingsPayload(d *schema.ResourceData) map[string]interface{} {
+	payload := map[string]interface{}{}
+	for _, k := range []string{"settings", "sensitive_settings"} {
+		for key, raw := range d.Get(k).(map[string]interface{}) {
+			var value interface{}
+			json.Unmarshal([]byte(raw.(string)), &value)
+			payload[key] = value
+		}
+	}
+	return payload
+}
+
+// flattenSettings encodes the current value of the managed settings, secrets AWX hides are kept as is.
+func flattenSettings(managed map[string]interface{}, current map[string]interface{}) (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+	for key, old := range managed {
+		value, ok := current[key]
+		if !ok {
+			log.Printf("[WARN] Setting %s does not exist anymore", key)
+			continue
+		}
+		if value == encryptedValue {
+			values[key] = old
+			continue
+		}
+		b, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		values[key] = string(b)
+	}
+	return values, nil
+}
+
+// resetSettings sets back keys of category to the defaults AWX advertises.
+func (c *Client) resetSettings(category string, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	var options struct {
+		Actions struct {
+			PUT map[string]map[string]interface{} `json:"PUT"`
+		} `json:"actions"`
+	}
+	if err := c.apiRequest("OPTIONS", settingsEndpoint(category), nil, &options, nil); err != nil {
+		return err
+	}
+	payload := map[string]interface{}{}
+	for _, key := range keys {
+		field, ok := options.Actions.PUT[key]
+		if !ok {
+			log.Printf("[WARN] Setting %s is not writable anymore, not resetting it", key)
+			continue
+		}
+		payload[key] = field["default"]
+	}
+	if len(payload) == 0 {
+		return nil
+	}
+	return c.apiPatch(settingsEndpoint(category), payload, nil)
+}
@@ -0,0 +1,831 @@
+package awx
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+// LDAP server slots, AWX stores the default one under AUTH_LDAP_* and the others under AUTH_LDAP_<n>_*
+var ldapServerSlots = []string{"default_server", "server_1", "server_2", "server_3", "server_4", "server_5"}
+
+var ldapSettingKeys = []string{
+	"SERVER_URI", "BIND_DN", "BIND_PASSWORD", "START_TLS", "CONNECTION_OPTIONS",
+	"USER_SEARCH", "USER_DN_TEMPLATE", "USER_ATTR_MAP",
+	"GROUP_SEARCH", "GROUP_TYPE", "GROUP_TYPE_PARAMS", "REQUIRE_GROUP", "DENY_GROUP",
+	"USER_FLAGS_BY_GROUP", "ORGANIZATION_MAP", "TEAM_MAP",
+}
+
+var ldapSearchScopes = []string{"SCOPE_BASE", "SCOPE_ONELEVEL", "SCOPE_SUBTREE"}
+
+// Parameters accepted by the django-auth-ldap group types (required ones first)
+var ldapGroupTypes = map[string]struct{ required, optional []string }{
+	"PosixGroupType":                    {nil, []string{"name_attr"}},
+	"PosixUIDGroupType":                 {nil, []string{"name_attr", "ldap_group_user_attr"}},
+	"GroupOfNamesType":                  {nil, []string{"name_attr"}},
+	"GroupOfUniqueNamesType":            {nil, []string{"name_attr"}},
+	"ActiveDirectoryGroupType":          {nil, []string{"name_attr"}},
+	"OrganizationalRoleGroupType":       {nil, []string{"name_attr"}},
+	"MemberDNGroupType":                 {[]string{"member_attr"}, []string{"name_attr"}},
+	"NestedGroupOfNamesType":            {nil, []string{"name_attr"}},
+	"NestedGroupOfUniqueNamesType":      {nil, []string{"name_attr"}},
+	"NestedActiveDirectoryGroupType":    {nil, []string{"name_attr"}},
+	"NestedOrganizationalRoleGroupType": {nil, []string{"name_attr"}},
+	"NestedMemberDNGroupType":           {[]string{"member_attr"}, []string{"name_attr"}},
+}
+
+func resourceSettingsLDAPObject() *schema.Resource {
+	s := map[string]*schema.Schema{}
+	for i, slot := range ldapServerSlots {
+		description := fmt.Sprintf("LDAP server number %d.", i)
+		if i == 0 {
+			description = "Default LDAP server."
+		}
+		s[slot] = &schema.Schema{
+			Type:        schema.TypeList,
+			Optional:    true,
+			MaxItems:    1,
+			Elem:        ldapServerSchema(),
+			Description: description,
+		}
+	}
+
+	return &schema.Resource{
+		Create:        resourceSettingsLDAPCreate,
+		Read:          resourceSettingsLDAPRead,
+		Delete:        resourceSettingsLDAPDelete,
+		Update:        resourceSettingsLDAPUpdate,
+		CustomizeDiff: resourceSettingsLDAPCustomizeDiff,
+		Schema:        s,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(1 * time.Minute),
+			Update: schema.DefaultTimeout(1 * time.Minute),
+			Delete: schema.DefaultTimeout(1 * time.Minute),
+		},
+	}
+}
+
+func ldapServerSchema() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"server_uri": &schema.Schema{
+				Type:     schema.TypeList,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringMatch(regexp.MustCompile(`^ldaps?://[^\s,]+$`), "must be an ldap:// or ldaps:// URI"),
+				},
+				Description: "URIs of the LDAP server, tried in order.",
+			},
+			"bind_dn": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "",
+				ValidateFunc: validateOptionalDN,
+				Description:  "DN of the user to bind as for the searches, anonymous bind if empty.",
+			},
+			"bind_password": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Sensitive:   true,
+				Description: "Password of the bind user.",
+			},
+			"start_tls": &schema.Schema{
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether to enable TLS when the connection is not using ldaps://.",
+			},
+			"connection_options": &schema.Schema{
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+				Description: "python-ldap options of the connection (e.g. OPT_REFERRALS = 0).",
+			},
+			"user_search": &schema.Schema{
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        ldapSearchSchema("(uid=%(user)s)"),
+				Description: "Searches to find the users, several searches are combined (LDAPSearchUnion).",
+			},
+			"user_dn_template": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "",
+				ValidateFunc: validateLDAPUserDNTemplate,
+				Description:  "DN of the users with %(user)s as a placeholder, used instead of user_search.",
+			},
+			"user_attr_map": &schema.Schema{
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "LDAP attribute of the user fields (first_name, last_name and email).",
+			},
+			"group_search": &schema.Schema{
+				Type:        schema.TypeList,
+				Optional:    true,
+				MaxItems:    1,
+				Elem:        ldapSearchSchema("(objectClass=group)"),
+				Description: "Search to find the groups of the users.",
+			},
+			"group_type": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "MemberDNGroupType",
+				ValidateFunc: validation.StringInSlice(ldapGroupTypeNames(), false),
+				Description:  "One of: " + strings.Join(ldapGroupTypeNames(), ", "),
+			},
+			"group_type_params": &schema.Schema{
+				Type:        schema.TypeMap,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Parameters of the group type (e.g. member_attr and name_attr), defaults to the ones of AWX.",
+			},
+			"require_group": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "",
+				ValidateFunc: validateOptionalDN,
+				Description:  "DN of the group the users must belong to.",
+			},
+			"deny_group": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "",
+				ValidateFunc: validateOptionalDN,
+				Description:  "DN of the group the users must not belong to.",
+			},
+			"user_flags_by_group": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"is_superuser": &schema.Schema{
+							Type:        schema.TypeList,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString, ValidateFunc: validateDN},
+							Description: "DNs of the groups whose members are superusers.",
+						},
+						"is_system_auditor": &schema.Schema{
+							Type:        schema.TypeList,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString, ValidateFunc: validateDN},
+							Description: "DNs of the groups whose members are system auditors.",
+						},
+					},
+				},
+				Description: "Groups granting user flags.",
+			},
+			"organization_map": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": &schema.Schema{
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Name of the organization.",
+						},
+						"admins": &schema.Schema{
+							Type:        schema.TypeList,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString, ValidateFunc: validateDN},
+							Description: "DNs of the groups whose members are administrators of the organization.",
+						},
+						"users": &schema.Schema{
+							Type:        schema.TypeList,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString, ValidateFunc: validateDN},
+							Description: "DNs of the groups whose members are members of the organization.",
+						},
+						"remove_admins": &schema.Schema{
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "Remove the administrators not matching admins on login.",
+						},
+						"remove_users": &schema.Schema{
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "Remove the members not matching users on login.",
+						},
+					},
+				},
+				Description: "Organizations the users are added to, given their groups.",
+			},
+			"team_map": &schema.Schema{
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": &schema.Schema{
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Name of the team.",
+						},
+						"organization": &schema.Schema{
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Name of the organization of the team.",
+						},
+						"users": &schema.Schema{
+							Type:        schema.TypeList,
+							Optional:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString, ValidateFunc: validateDN},
+							Description: "DNs of the groups whose members are members of the team.",
+						},
+						"remove": &schema.Schema{
+							Type:        schema.TypeBool,
+							Optional:    true,
+							Default:     false,
+							Description: "Remove the members not matching users on login.",
+						},
+					},
+				},
+				Description: "Teams the users are added to, given their groups.",
+			},
+		},
+	}
+}
+
+func ldapSearchSchema(filterExample string) *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"base_dn": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateDN,
+				Description:  "DN to search from.",
+			},
+			"scope": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "SCOPE_SUBTREE",
+				ValidateFunc: validation.StringInSlice(ldapSearchScopes, false),
+				Description:  "One of: " + strings.Join(ldapSearchScopes, ", "),
+			},
+			"filter": &schema.Schema{
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validateLDAPFilter,
+				Description:  fmt.Sprintf("LDAP filter, e.g. %s.", filterExample),
+			},
+		},
+	}
+}
+
+func resourceSettingsLDAPCreate(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	if err := awx.apiPatch(settingsEndpoint("ldap"), settingsLDAPPayload(d), nil); err != nil {
+		return err
+	}
+
+	d.SetId("ldap")
+	return resourceSettingsLDAPRead(d, m)
+}
+
+func resourceSettingsLDAPUpdate(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	if err := awx.apiPatch(settingsEndpoint("ldap"), settingsLDAPPayload(d), nil); err != nil {
+		return err
+	}
+
+	// Servers no longer managed are given back to AWX
+	var removed []string
+	for i, slot := range ldapServerSlots {
+		old, new := d.GetChange(slot)
+		if len(old.([]interface{})) > 0 && len(new.([]interface{})) == 0 {
+			removed = append(removed, ldapSlotKeys(i)...)
+		}
+	}
+	if err := awx.resetSettings("ldap", removed); err != nil {
+		return err
+	}
+
+	return resourceSettingsLDAPRead(d, m)
+}
+
+func resourceSettingsLDAPRead(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	var current map[string]interface{}
+	if err := awx.apiGet(settingsEndpoint("ldap"), &current, nil); err != nil {
+		if isNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	for i, slot := range ldapServerSlots {
+		var old map[string]interface{}
+		if blocks := d.Get(slot).([]interface{}); len(blocks) > 0 && blocks[0] != nil {
+			old = blocks[0].(map[string]interface{})
+		}
+		d.Set(slot, flattenLDAPServer(ldapSlotPrefix(i), current, old))
+	}
+	return nil
+}
+
+func resourceSettingsLDAPDelete(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	var keys []string
+	for i, slot := range ldapServerSlots {
+		if len(d.Get(slot).([]interface{})) > 0 {
+			keys = append(keys, ldapSlotKeys(i)...)
+		}
+	}
+	if err := awx.resetSettings("ldap", keys); err != nil {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
+
+// resourceSettingsLDAPCustomizeDiff checks what depends on several fields of a server.
+func resourceSettingsLDAPCustomizeDiff(d *schema.ResourceDiff, m interface{}) error {
+	for _, slot := range ldapServerSlots {
+		blocks := d.Get(slot).([]interface{})
+		if len(blocks) == 0 || blocks[0] == nil {
+			continue
+		}
+		server := blocks[0].(map[string]interface{})
+
+		groupType := server["group_type"].(string)
+		if params, ok := server["group_type_params"].(map[string]interface{}); ok && len(params) > 0 {
+			if err := validateLDAPGroupTypeParams(groupType, params); err != nil {
+				return fmt.Errorf("%s: %s", slot, err)
+			}
+		}
+
+		organizations := map[string]bool{}
+		for _, raw := range server["organization_map"].([]interface{}) {
+			name := raw.(map[string]interface{})["name"].(string)
+			if organizations[name] {
+				return fmt.Errorf("%s: organization %q is mapped more than once", slot, name)
+			}
+			organizations[name] = true
+		}
+		teams := map[string]bool{}
+		for _, raw := range server["team_map"].([]interface{}) {
+			name := raw.(map[string]interface{})["name"].(string)
+			if teams[name] {
+				return fmt.Errorf("%s: team %q is mapped more than once", slot, name)
+			}
+			teams[name] = true
+		}
+	}
+	return nil
+}
+
+func ldapSlotPrefix(i int) string {
+	if i == 0 {
+		return "AUTH_LDAP_"
+	}
+	return fmt.Sprintf("AUTH_LDAP_%d_", i)
+}
+
+func ldapSlotKeys(i int) []string {
+	keys := []string{}
+	for _, key := range ldapSettingKeys {
+		keys = append(keys, ldapSlotPrefix(i)+key)
+	}
+	return keys
+}
+
+func ldapGroupTypeNames() []string {
+	names := []string{}
+	for name := range ldapGroupTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func settingsLDAPPayload(d *schema.ResourceData) map[string]interface{} {
+	payload := map[string]interface{}{}
+	for i, slot := range ldapServerSlots {
+		blocks := d.Get(slot).([]interface{})
+		if len(blocks) == 0 || blocks[0] == nil {
+			continue
+		}
+		for k, v := range expandLDAPServer(ldapSlotPrefix(i), blocks[0].(map[string]interface{})) {
+			payload[k] = v
+		}
+	}
+	return payload
+}
+
+func expandLDAPServer(prefix string, server map[string]interface{}) map[string]interface{} {
+	payload := map[string]interface{}{
+		prefix + "SERVER_URI":       strings.Join(expandStringList(server["server_uri"]), " "),
+		prefix + "BIND_DN":          server["bind_dn"].(string),
+		prefix + "BIND_PASSWORD":    server["bind_password"].(string),
+		prefix + "START_TLS":        server["start_tls"].(bool),
+		prefix + "USER_DN_TEMPLATE": nullIfEmpty(server["user_dn_template"].(string)),
+		prefix + "USER_ATTR_MAP":    server["user_attr_map"],
+		prefix + "GROUP_TYPE":       server["group_type"].(string),
+		prefix + "REQUIRE_GROUP":    nullIfEmpty(server["require_group"].(string)),
+		prefix + "DENY_GROUP":       nullIfEmpty(server["deny_group"].(string)),
+	}
+	if options := server["connection_options"].(map[string]interface{}); len(options) > 0 {
+		payload[prefix+"CONNECTION_OPTIONS"] = options
+	}
+	payload[prefix+"GROUP_TYPE_PARAMS"] = ldapDefaultGroupTypeParams(server["group_type"].(string))
+	if params := server["group_type_params"].(map[string]interface{}); len(params) > 0 {
+		payload[prefix+"GROUP_TYPE_PARAMS"] = params
+	}
+
+	searches := []interface{}{}
+	for _, raw := range server["user_search"].([]interface{}) {
+		searches = append(searches, expandLDAPSearch(raw))
+	}
+	switch len(searches) {
+	case 0:
+		payload[prefix+"USER_SEARCH"] = []interface{}{}
+	case 1:
+		payload[prefix+"USER_SEARCH"] = searches[0]
+	default:
+		payload[prefix+"USER_SEARCH"] = searches
+	}
+	payload[prefix+"GROUP_SEARCH"] = []interface{}{}
+	if groupSearch := server["group_search"].([]interface{}); len(groupSearch) > 0 {
+		payload[prefix+"GROUP_SEARCH"] = expandLDAPSearch(groupSearch[0])
+	}
+
+	flags := map[string]interface{}{}
+	if blocks := server["user_flags_by_group"].([]interface{}); len(blocks) > 0 && blocks[0] != nil {
+		block := blocks[0].(map[string]interface{})
+		for _, flag := range []string{"is_superuser", "is_system_auditor"} {
+			if groups := expandStringList(block[flag]); len(groups) > 0 {
+				flags[flag] = groups
+			}
+		}
+	}
+	payload[prefix+"USER_FLAGS_BY_GROUP"] = flags
+
+	organizations := map[string]interface{}{}
+	for _, raw := range server["organization_map"].([]interface{}) {
+		block := raw.(map[string]interface{})
+		organizations[block["name"].(string)] = map[string]interface{}{
+			"admins":        expandStringList(block["admins"]),
+			"users":         expandStringList(block["users"]),
+			"remove_admins": block["remove_admins"].(bool),
+			"remove_users":  block["remove_users"].(bool),
+		}
+	}
+	payload[prefix+"ORGANIZATION_MAP"] = organizations
+
+	teams := map[string]interface{}{}
+	for _, raw := range server["team_map"].([]interface{}) {
+		block := raw.(map[string]interface{})
+		teams[block["name"].(string)] = map[string]interface{}{
+			"organization": block["organization"].(string),
+			"users":        expandStringList(block["users"]),
+			"remove":       block["remove"].(bool),
+		}
+	}
+	payload[prefix+"TEAM_MAP"] = teams
+
+	return payload
+}
+
+func expandLDAPSearch(raw interface{}) []interface{} {
+	block := raw.(map[string]interface{})
+	return []interface{}{block["base_dn"].(string), block["scope"].(string), block["filter"].(string)}
+}
+
+// flattenLDAPServer returns the server block of prefix, or no block if the server is not configured.
+func flattenLDAPServer(prefix string, current map[string]interface{}, old map[string]interface{}) []interface{} {
+	uri, _ := current[prefix+"SERVER_URI"].(string)
+	if uri == "" {
+		return []interface{}{}
+	}
+	server := map[string]interface{}{
+		"server_uri":         strings.FieldsFunc(uri, func(r rune) bool { return r == ' ' || r == ',' }),
+		"bind_dn":            stringOrEmpty(current[prefix+"BIND_DN"]),
+		"bind_password":      stringOrEmpty(current[prefix+"BIND_PASSWORD"]),
+		"start_tls":          current[prefix+"START_TLS"] == true,
+		"connection_options": current[prefix+"CONNECTION_OPTIONS"],
+		"user_dn_template":   stringOrEmpty(current[prefix+"USER_DN_TEMPLATE"]),
+		"user_attr_map":      current[prefix+"USER_ATTR_MAP"],
+		"group_type":         stringOrEmpty(current[prefix+"GROUP_TYPE"]),
+		"group_type_params":  current[prefix+"GROUP_TYPE_PARAMS"],
+		"require_group":      stringOrEmpty(current[prefix+"REQUIRE_GROUP"]),
+		"deny_group":         stringOrEmpty(current[prefix+"DENY_GROUP"]),
+	}
+	if server["bind_password"] == encryptedValue && old != nil {
+		server["bind_password"] = old["bind_password"]
+	}
+	if params, ok := current[prefix+"GROUP_TYPE_PARAMS"].(map[string]interface{}); ok && old != nil &&
+		len(old["group_type_params"].(map[string]interface{})) == 0 &&
+		reflect.DeepEqual(params, ldapDefaultGroupTypeParams(server["group_type"].(string))) {
+		server["group_type_params"] = map[string]interface{}{}
+	}
+
+	// A single search is a [base_dn, scope, filter] triple, a union is a list of those
+	userSearch := []interface{}{}
+	if searches, ok := current[prefix+"USER_SEARCH"].([]interface{}); ok && len(searches) > 0 {
+		if _, nested := searches[0].([]interface{}); !nested {
+			searches = []interface{}{searches}
+		}
+		for _, search := range searches {
+			if block := flattenLDAPSearch(search); block != nil {
+				userSearch = append(userSearch, block)
+			}
+		}
+	}
+	server["user_search"] = userSearch
+	server["group_search"] = []interface{}{}
+	if block := flattenLDAPSearch(current[prefix+"GROUP_SEARCH"]); block != nil {
+		server["group_search"] = []interface{}{block}
+	}
+
+	server["user_flags_by_group"] = []interface{}{}
+	if flags, ok := current[prefix+"USER_FLAGS_BY_GROUP"].(map[string]interface{}); ok && len(flags) > 0 {
+		server["user_flags_by_group"] = []interface{}{map[string]interface{}{
+			"is_superuser":      ldapGroupList(flags["is_superuser"]),
+			"is_system_auditor": ldapGroupList(flags["is_system_auditor"]),
+		}}
+	}
+
+	organizations := []interface{}{}
+	if mapping, ok := current[prefix+"ORGANIZATION_MAP"].(map[string]interface{}); ok {
+		for _, name := range sortedKeys(mapping) {
+			organization, _ := mapping[name].(map[string]interface{})
+			organizations = append(organizations, map[string]interface{}{
+				"name":          name,
+				"admins":        ldapGroupList(organization["admins"]),
+				"users":         ldapGroupList(organization["users"]),
+				"remove_admins": organization["remove_admins"] == true,
+				"remove_users":  organization["remove_users"] == true,
+			})
+		}
+	}
+	server["organization_map"] = orderLikeConfig(organizations, old, "organization_map")
+
+	teams := []interface{}{}
+	if mapping, ok := current[prefix+"TEAM_MAP"].(map[string]interface{}); ok {
+		for _, name := range sortedKeys(mapping) {
+			team, _ := mapping[name].(map[string]interface{})
+			teams = append(teams, map[string]interface{}{
+				"name":         name,
+				"organization": stringOrEmpty(team["organization"]),
+				"users":        ldapGroupList(team["users"]),
+				"remove":       team["remove"] == true,
+			})
+		}
+	}
+	server["team_map"] = orderLikeConfig(teams, old, "team_map")
+
+	return []interface{}{server}
+}
+
+func flattenLDAPSearch(raw interface{}) map[string]interface{} {
+	search, ok := raw.([]interface{})
+	if !ok || len(search) != 3 {
+		return nil
+	}
+	return map[string]interface{}{
+		"base_dn": stringOrEmpty(search[0]),
+		"scope":   stringOrEmpty(search[1]),
+		"filter":  stringOrEmpty(search[2]),
+	}
+}
+
+// ldapGroupList converts the group(s) of a mapping to a list, AWX also accepts a single DN (or a boolean).
+func ldapGroupList(raw interface{}) []interface{} {
+	switch v := raw.(type) {
+	case string:
+		return []interface{}{v}
+	case []interface{}:
+		return v
+	}
+	return []interface{}{}
+}
+
+// orderLikeConfig sorts the named blocks as in the previous state, AWX returns mappings as objects.
+func orderLikeConfig(blocks []interface{}, old map[string]interface{}, key string) []interface{} {
+	if old == nil {
+		return blocks
+	}
+	position := map[string]int{}
+	for i, raw := range old[key].([]interface{}) {
+		position[raw.(map[string]interface{})["name"].(string)] = i
+	}
+	sort.SliceStable(blocks, func(i, j int) bool {
+		pi, oki := position[blocks[i].(map[string]interface{})["name"].(string)]
+		pj, okj := position[blocks[j].(map[string]interface{})["name"].(string)]
+		if oki && okj {
+			return pi < pj
+		}
+		return oki && !okj
+	})
+	return blocks
+}
+
+// ldapDefaultGroupTypeParams returns the parameters AWX uses when none are given.
+func ldapDefaultGroupTypeParams(groupType string) map[string]interface{} {
+	params := map[string]interface{}{"name_attr": "cn"}
+	if spec, ok := ldapGroupTypes[groupType]; ok && stringInSlice("member_attr", spec.required) {
+		params["member_attr"] = "member"
+	}
+	return params
+}
+
+func validateLDAPGroupTypeParams(groupType string, params map[string]interface{}) error {
+	spec, ok := ldapGroupTypes[groupType]
+	if !ok {
+		return nil
+	}
+	allowed := append(append([]string{}, spec.required...), spec.optional...)
+	for key := range params {
+		if !stringInSlice(key, allowed) {
+			return fmt.Errorf("%s does not accept the %q parameter (accepted: %s)", groupType, key, strings.Join(allowed, ", "))
+		}
+	}
+	for _, key := range spec.required {
+		if _, ok := params[key]; !ok {
+			return fmt.Errorf("%s requires the %q parameter", groupType, key)
+		}
+	}
+	return nil
+}
+
+var (
+	ldapAttributeType = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9-]*|[0-9]+(\.[0-9]+)*)$`)
+	ldapHexPair       = regexp.MustCompile(`^[0-9A-Fa-f]{2}$`)
+)
+
+// validateDN checks the syntax of a distinguished name (RFC 4514).
+func validateDN(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+	if err := parseDN(value); err != nil {
+		errors = append(errors, fmt.Errorf("%q: %q is not a valid DN: %s", k, value, err))
+	}
+	return
+}
+
+func validateOptionalDN(v interface{}, k string) (ws []string, errors []error) {
+	if v.(string) == "" {
+		return
+	}
+	return validateDN(v, k)
+}
+
+func validateLDAPUserDNTemplate(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+	if value == "" {
+		return
+	}
+	if !strings.Contains(value, "%(user)s") {
+		errors = append(errors, fmt.Errorf("%q: must contain the %%(user)s placeholder", k))
+		return
+	}
+	return validateDN(strings.Replace(value, "%(user)s", "user", -1), k)
+}
+
+// validateLDAPFilter checks the filter is parenthesized and its parentheses are balanced.
+func validateLDAPFilter(v interface{}, k string) (ws []string, errors []error) {
+	value := v.(string)
+	if !strings.HasPrefix(value, "(") || !strings.HasSuffix(value, ")") {
+		errors = append(errors, fmt.Errorf("%q: filter must be enclosed in parentheses", k))
+		return
+	}
+	depth := 0
+	for i, c := range value {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+		}
+		if depth < 0 || (depth == 0 && i < len(value)-1) {
+			errors = append(errors, fmt.Errorf("%q: unbalanced parentheses at position %d", k, i))
+			return
+		}
+	}
+	if depth != 0 {
+		errors = append(errors, fmt.Errorf("%q: unbalanced parentheses", k))
+	}
+	return
+}
+
+// parseDN splits the DN in relative DNs (on unescaped commas), then in attribute type and value assertions
+// (on unescaped plus signs) and checks each of them.
+func parseDN(dn string) error {
+	if strings.TrimSpace(dn) == "" {
+		return fmt.Errorf("empty DN")
+	}
+	for _, rdn := range splitUnescaped(dn, ',') {
+		for _, ava := range splitUnescaped(rdn, '+') {
+			parts := strings.SplitN(ava, "=", 2)
+			if len(parts) != 2 {
+				return fmt.Errorf("missing '=' in %q", strings.TrimSpace(ava))
+			}
+			attribute, value := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+			if !ldapAttributeType.MatchString(attribute) {
+				return fmt.Errorf("invalid attribute type %q", attribute)
+			}
+			if value == "" {
+				return fmt.Errorf("empty value for %s", attribute)
+			}
+			if err := checkDNValue(value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func splitUnescaped(s string, sep byte) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' {
+			i++
+		} else if s[i] == sep {
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+func checkDNValue(value string) error {
+	if strings.HasPrefix(value, "#") {
+		if len(value)%2 != 1 || len(value) < 3 {
+			return fmt.Errorf("invalid hex string %q", value)
+		}
+		for i := 1; i < len(value); i += 2 {
+			if !ldapHexPair.MatchString(value[i : i+2]) {
+				return fmt.Errorf("invalid hex string %q", value)
+			}
+		}
+		return nil
+	}
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '\\':
+			if i+1 >= len(value) {
+				return fmt.Errorf("dangling escape in %q", value)
+			}
+			if strings.IndexByte(`"+,;<>\ #=`, value[i+1]) >= 0 {
+				i++
+			} else if i+2 < len(value) && ldapHexPair.MatchString(value[i+1:i+3]) {
+				i += 2
+			} else {
+				return fmt.Errorf("invalid escape in %q", value)
+			}
+		case '"', ';', '<', '>':
+			return fmt.Errorf("unescaped %q in %q", value[i], value)
+		}
+	}
+	return nil
+}
+
+func expandStringList(raw interface{}) []string {
+	values := []string{}
+	if list, ok := raw.([]interface{}); ok {
+		for _, v := range list {
+			values = append(values, v.(string))
+		}
+	}
+	return values
+}
+
+func stringOrEmpty(raw interface{}) string {
+	if s, ok := raw.(string); ok {
+		return s
+	}
+	return ""
+}
+
+func nullIfEmpty(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,251 @@
+package awx
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// awx_settings_ldap test case, against a local stub of the settings endpoints
+func TestAWXSettingsLDAP(t *testing.T) {
+	defaults := map[string]interface{}{}
+	for i := range ldapServerSlots {
+		prefix := ldapSlotPrefix(i)
+		defaults[prefix+"SERVER_URI"] = ""
+		defaults[prefix+"BIND_DN"] = ""
+		defaults[prefix+"BIND_PASSWORD"] = ""
+		defaults[prefix+"START_TLS"] = false
+		defaults[prefix+"CONNECTION_OPTIONS"] = map[string]interface{}{"OPT_REFERRALS": 0, "OPT_NETWORK_TIMEOUT": 30}
+		defaults[prefix+"USER_SEARCH"] = []interface{}{}
+		defaults[prefix+"USER_DN_TEMPLATE"] = nil
+		defaults[prefix+"USER_ATTR_MAP"] = map[string]interface{}{}
+		defaults[prefix+"GROUP_SEARCH"] = []interface{}{}
+		defaults[prefix+"GROUP_TYPE"] = "MemberDNGroupType"
+		defaults[prefix+"GROUP_TYPE_PARAMS"] = map[string]interface{}{"member_attr": "member", "name_attr": "cn"}
+		defaults[prefix+"REQUIRE_GROUP"] = nil
+		defaults[prefix+"DENY_GROUP"] = nil
+		defaults[prefix+"USER_FLAGS_BY_GROUP"] = map[string]interface{}{}
+		defaults[prefix+"ORGANIZATION_MAP"] = map[string]interface{}{}
+		defaults[prefix+"TEAM_MAP"] = map[string]interface{}{}
+	}
+	stub := newSettingsStub(t, map[string]map[string]interface{}{"ldap": defaults}, "AUTH_LDAP_BIND_PASSWORD", "AUTH_LDAP_1_BIND_PASSWORD")
+	defer stub.Close()
+
+	provider := fmt.Sprintf("provider \"awx\" {\n\tendpoint = %q\n}\n", stub.URL)
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testAccProviders,
+		CheckDestroy: resource.ComposeTestCheckFunc(
+			testCheckSettingsStub(stub, "ldap", "AUTH_LDAP_SERVER_URI", `""`),
+			testCheckSettingsStub(stub, "ldap", "AUTH_LDAP_ORGANIZATION_MAP", `{}`),
+		),
+		Steps: []resource.TestStep{
+			{
+				Config: provider + testAccSettingsLDAPConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("awx_settings_ldap.ldap", "default_server.0.server_uri.#", "2"),
+					resource.TestCheckResourceAttr("awx_settings_ldap.ldap", "default_server.0.bind_password", "secret"),
+					resource.TestCheckResourceAttr("awx_settings_ldap.ldap", "default_server.0.organization_map.0.name", "Operations"),
+					resource.TestCheckResourceAttr("awx_settings_ldap.ldap", "server_1.0.group_type", "PosixGroupType"),
+					testCheckSettingsStub(stub, "ldap", "AUTH_LDAP_SERVER_URI", `"ldaps://ldap1.example.com ldaps://ldap2.example.com"`),
+					testCheckSettingsStub(stub, "ldap", "AUTH_LDAP_USER_SEARCH",
+						`[["ou=people,dc=example,dc=com","SCOPE_SUBTREE","(uid=%(user)s)"],["ou=robots,dc=example,dc=com","SCOPE_ONELEVEL","(cn=%(user)s)"]]`),
+					testCheckSettingsStub(stub, "ldap", "AUTH_LDAP_GROUP_SEARCH", `["ou=groups,dc=example,dc=com","SCOPE_SUBTREE","(objectClass=groupOfNames)"]`),
+					testCheckSettingsStub(stub, "ldap", "AUTH_LDAP_ORGANIZATION_MAP",
+						`{"Default":{"admins":[],"remove_admins":false,"remove_users":false,"users":["cn=awx,ou=groups,dc=example,dc=com"]},`+
+							`"Operations":{"admins":["cn=ops-admins,ou=groups,dc=example,dc=com"],"remove_admins":true,"remove_users":false,"users":["cn=ops,ou=groups,dc=example,dc=com"]}}`),
+					testCheckSettingsStub(stub, "ldap", "AUTH_LDAP_TEAM_MAP",
+						`{"Oncall":{"organization":"Operations","remove":true,"users":["cn=oncall,ou=groups,dc=example,dc=com"]}}`),
+					testCheckSettingsStub(stub, "ldap", "AUTH_LDAP_1_GROUP_TYPE_PARAMS", `{"name_attr":"cn"}`),
+				),
+			},
+			{
+				Config: provider + testAccSettingsLDAPDefaultOnlyConfig,
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("awx_settings_ldap.ldap", "server_1.#", "0"),
+					testCheckSettingsStub(stub, "ldap", "AUTH_LDAP_1_SERVER_URI", `""`),
+					testCheckSettingsStub(stub, "ldap", "AUTH_LDAP_1_GROUP_TYPE", `"MemberDNGroupType"`),
+					testCheckSettingsStub(stub, "ldap", "AUTH_LDAP_USER_SEARCH", `["ou=people,dc=example,dc=com","SCOPE_SUBTREE","(uid=%(user)s)"]`),
+				),
+			},
+			{
+				Config:      provider + testAccSettingsLDAPInvalidServer("scope = \"SCOPE_EVERYTHING\""),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`scope to be one of`),
+			},
+			{
+				Config:      provider + testAccSettingsLDAPInvalidServer("base_dn = \"ou=people;dc=example\""),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`is not a valid DN`),
+			},
+			{
+				Config:      provider + testAccSettingsLDAPInvalidServer("filter = \"(&(uid=%(user)s)\""),
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`unbalanced parentheses`),
+			},
+			{
+				Config:      provider + testAccSettingsLDAPInvalidGroupType,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile(`PosixGroupType does not accept the "member_attr" parameter`),
+			},
+		},
+	})
+}
+
+func TestParseDN(t *testing.T) {
+	valid := []string{
+		"dc=example,dc=com",
+		"CN=Doe\\, John,OU=Users,DC=example,DC=com",
+		"cn=a+sn=b,dc=example",
+		"uid=jdoe, ou=people, dc=example, dc=com",
+		"1.3.6.1.4.1.1466.0=#04024869,dc=example",
+		"cn=caf\\C3\\A9,dc=example",
+	}
+	for _, dn := range valid {
+		if err := parseDN(dn); err != nil {
+			t.Errorf("%q: unexpected error %s", dn, err)
+		}
+	}
+	invalid := []string{"", "example.com", "cn=,dc=com", "cn=a;b,dc=com", "c n=a", "cn=a\\", "cn=a\\zz", "=a", "cn=#0"}
+	for _, dn := range invalid {
+		if err := parseDN(dn); err == nil {
+			t.Errorf("%q: expected an error", dn)
+		}
+	}
+}
+
+func testCheckSettingsStub(stub *settingsStub, category, key, expected string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if actual := stub.get(category, key); actual != expected {
+			return fmt.Errorf("%s != %s (actual: %s)", key, expected, actual)
+		}
+		return nil
+	}
+}
+
+const testAccSettingsLDAPConfig = `
+resource "awx_settings_ldap" "ldap" {
+	default_server {
+		server_uri    = ["ldaps://ldap1.example.com", "ldaps://ldap2.example.com"]
+		bind_dn       = "cn=awx,ou=services,dc=example,dc=com"
+		bind_password = "secret"
+
+		user_search {
+			base_dn = "ou=people,dc=example,dc=com"
+			filter  = "(uid=%(user)s)"
+		}
+		user_search {
+			base_dn = "ou=robots,dc=example,dc=com"
+			scope   = "SCOPE_ONELEVEL"
+			filter  = "(cn=%(user)s)"
+		}
+		user_attr_map = {
+			first_name = "givenName"
+			last_name  = "sn"
+			email      = "mail"
+		}
+
+		group_search {
+			base_dn = "ou=groups,dc=example,dc=com"
+			filter  = "(objectClass=groupOfNames)"
+		}
+		group_type        = "GroupOfNamesType"
+		group_type_params = {
+			name_attr = "cn"
+		}
+
+		user_flags_by_group {
+			is_superuser = ["cn=awx-admins,ou=groups,dc=example,dc=com"]
+		}
+
+		organization_map {
+			name          = "Operations"
+			admins        = ["cn=ops-admins,ou=groups,dc=example,dc=com"]
+			users         = ["cn=ops,ou=groups,dc=example,dc=com"]
+			remove_admins = true
+		}
+		organization_map {
+			name  = "Default"
+			users = ["cn=awx,ou=groups,dc=example,dc=com"]
+		}
+
+		team_map {
+			name         = "Oncall"
+			organization = "Operations"
+			users        = ["cn=oncall,ou=groups,dc=example,dc=com"]
+			remove       = true
+		}
+	}
+
+	server_1 {
+		server_uri       = ["ldap://legacy.example.com"]
+		start_tls        = true
+		user_dn_template = "uid=%(user)s,ou=people,dc=legacy,dc=example"
+
+		group_search {
+			base_dn = "ou=groups,dc=legacy,dc=example"
+			filter  = "(objectClass=posixGroup)"
+		}
+		group_type        = "PosixGroupType"
+		group_type_params = {
+			name_attr = "cn"
+		}
+	}
+}
+`
+
+const testAccSettingsLDAPDefaultOnlyConfig = `
+resource "awx_settings_ldap" "ldap" {
+	default_server {
+		server_uri    = ["ldaps://ldap1.example.com", "ldaps://ldap2.example.com"]
+		bind_dn       = "cn=awx,ou=services,dc=example,dc=com"
+		bind_password = "secret"
+
+		user_search {
+			base_dn = "ou=people,dc=example,dc=com"
+			filter  = "(uid=%(user)s)"
+		}
+	}
+}
+`
+
+func testAccSettingsLDAPInvalidServer(override string) string {
+	search := map[string]string{
+		"base_dn": `base_dn = "ou=people,dc=example,dc=com"`,
+		"scope":   `scope = "SCOPE_SUBTREE"`,
+		"filter":  `filter = "(uid=%(user)s)"`,
+	}
+	for key := range search {
+		if regexp.MustCompile(`^` + key + ` `).MatchString(override) {
+			search[key] = override
+		}
+	}
+	return fmt.Sprintf(`
+resource "awx_settings_ldap" "ldap" {
+	default_server {
+		server_uri = ["ldaps://ldap1.example.com"]
+
+		user_search {
+			%s
+			%s
+			%s
+		}
+	}
+}
+`, search["base_dn"], search["scope"], search["filter"])
+}
+
+const testAccSettingsLDAPInvalidGroupType = `
+resource "awx_settings_ldap" "ldap" {
+	default_server {
+		server_uri        = ["ldaps://ldap1.example.com"]
+		group_type        = "PosixGroupType"
+		group_type_params = {
+			member_attr = "member"
+		}
+	}
+}
+`
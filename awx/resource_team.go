@@ -48,7 +48,7 @@ func resourceTeamObject() *schema.Resource {
 }
 
 func resourceTeamCreate(d *schema.ResourceData, m interface{}) error {
-	awx := m.(*awxgo.AWX)
+	awx := m.(*Client)
 	awxService := awx.TeamService
 	_, res, err := awxService.ListTeams(map[string]string{
 		"name": d.Get("name").(string)})
@@ -74,7 +74,7 @@ func resourceTeamCreate(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceTeamUpdate(d *schema.ResourceData, m interface{}) error {
-	awx := m.(*awxgo.AWX)
+	awx := m.(*Client)
 	awxService := awx.TeamService
 	_, res, err := awxService.ListTeams(map[string]string{
 		"id": d.Id()},
@@ -103,7 +103,7 @@ func resourceTeamUpdate(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceTeamRead(d *schema.ResourceData, m interface{}) error {
-	awx := m.(*awxgo.AWX)
+	awx := m.(*Client)
 	awxService := awx.TeamService
 	_, res, err := awxService.ListTeams(map[string]string{
 		"name": d.Get("name").(string)})
@@ -118,7 +118,7 @@ func resourceTeamRead(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceTeamDelete(d *schema.ResourceData, m interface{}) error {
-	awx := m.(*awxgo.AWX)
+	awx := m.(*Client)
 	awxService := awx.TeamService
 	id, err := strconv.Atoi(d.Id())
 	if err != nil {
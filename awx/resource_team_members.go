@@ -0,0 +1,92 @@
+package awx
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func resourceTeamMembersObject() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceTeamMembersUpdate,
+		Read:   resourceTeamMembersRead,
+		Delete: resourceTeamMembersDelete,
+		Update: resourceTeamMembersUpdate,
+
+		Schema: map[string]*schema.Schema{
+			"team_id": &schema.Schema{
+				Type:        schema.TypeInt,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Numeric ID of the team.",
+			},
+			"user_ids": &schema.Schema{
+				Type:        schema.TypeSet,
+				Required:    true,
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+				Set:         schema.HashInt,
+				Description: "Numeric IDs of the members of the team (authoritative, other members are removed).",
+			},
+		},
+		Importer: &schema.ResourceImporter{
+			State: importTeamMembersData,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(1 * time.Minute),
+			Update: schema.DefaultTimeout(1 * time.Minute),
+			Delete: schema.DefaultTimeout(1 * time.Minute),
+		},
+	}
+}
+
+func resourceTeamMembersUpdate(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	teamID := d.Get("team_id").(int)
+	if err := awx.apiReconcile(teamMembersEndpoint(teamID), expandIDSet(d.Get("user_ids"))); err != nil {
+		return err
+	}
+
+	d.SetId(strconv.Itoa(teamID))
+	return resourceTeamMembersRead(d, m)
+}
+
+func resourceTeamMembersRead(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	users, err := awx.apiListIDs(teamMembersEndpoint(d.Get("team_id").(int)))
+	if err != nil {
+		if isNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	// Members added out of band show up as drift
+	d.Set("user_ids", users)
+	return nil
+}
+
+// resourceTeamMembersDelete removes all the members of the team.
+func resourceTeamMembersDelete(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	if err := awx.apiReconcile(teamMembersEndpoint(d.Get("team_id").(int)), []int{}); err != nil && !isNotFound(err) {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
+
+func importTeamMembersData(d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	teamID, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return nil, fmt.Errorf("Invalid team ID %q", d.Id())
+	}
+	d.Set("team_id", teamID)
+	return []*schema.ResourceData{d}, nil
+}
+
+func teamMembersEndpoint(teamID int) string {
+	return fmt.Sprintf("/api/v2/teams/%d/users/", teamID)
+}
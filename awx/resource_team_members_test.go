@@ -0,0 +1,74 @@
+package awx
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// awx_team_members test case
+func TestAccAWXTeamMembers(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { TestAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTeamMembersConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckStateTeamMembers("user_ids.#", "2"),
+				),
+			},
+			{
+				ResourceName:      "awx_team_members.testacc-team_1",
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccCheckStateTeamMembers(skey, svalue string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources["awx_team_members.testacc-team_1"]
+		if !ok {
+			return fmt.Errorf("awx_team_members.testacc-team_1 not found")
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		cr := rs.Primary
+
+		if cr.Attributes[skey] != svalue {
+			return fmt.Errorf("%s != %s (actual: %s)", skey, svalue, cr.Attributes[skey])
+		}
+
+		return nil
+	}
+}
+
+const testAccTeamMembersConfig = `
+resource "awx_team" "testacc-team_1" {
+	name            = "testacc-team_1"
+	organization_id = "1"
+}
+
+resource "awx_user" "testacc-user_1" {
+	username = "testacc-user_1"
+	password = "password"
+	email    = "testacc-user_1@test.td"
+}
+
+resource "awx_user" "testacc-user_2" {
+	username = "testacc-user_2"
+	password = "password"
+	email    = "testacc-user_2@test.td"
+}
+
+resource "awx_team_members" "testacc-team_1" {
+	team_id  = "${awx_team.testacc-team_1.id}"
+	user_ids = ["${awx_user.testacc-user_1.id}", "${awx_user.testacc-user_2.id}"]
+}
+`
@@ -0,0 +1,155 @@
+package awx
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+func resourceTokenObject() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceTokenCreate,
+		Read:   resourceTokenRead,
+		Delete: resourceTokenDelete,
+		Update: resourceTokenUpdate,
+
+		Schema: map[string]*schema.Schema{
+			"user_id": &schema.Schema{
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "Numeric ID of the user owning the token, the provider user if unset.",
+			},
+			"application_id": &schema.Schema{
+				Type:        schema.TypeInt,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "Numeric ID of the application the token is issued for, a personal access token if unset.",
+			},
+			"scope": &schema.Schema{
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "write",
+				ValidateFunc: validation.StringInSlice([]string{"read", "write"}, false),
+				Description:  "One of: read, write",
+			},
+			"description": &schema.Schema{
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "",
+				Description: "Optional description of this token.",
+			},
+
+			// Computed fields
+			"token": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The token, only known when it is created.",
+			},
+			"refresh_token": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "The refresh token (application tokens only), only known when it is created.",
+			},
+			"expires": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Expiration date of the token.",
+			},
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(1 * time.Minute),
+			Update: schema.DefaultTimeout(1 * time.Minute),
+			Delete: schema.DefaultTimeout(1 * time.Minute),
+		},
+	}
+}
+
+func resourceTokenCreate(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	payload := tokenPayload(d)
+
+	endpoint := "/api/v2/tokens/"
+	application, hasApplication := d.GetOk("application_id")
+	if hasApplication {
+		payload["application"] = application.(int)
+	}
+	if user, ok := d.GetOk("user_id"); ok {
+		if hasApplication {
+			endpoint = fmt.Sprintf("/api/v2/users/%d/tokens/", user.(int))
+		} else {
+			endpoint = fmt.Sprintf("/api/v2/users/%d/personal_tokens/", user.(int))
+		}
+	}
+
+	result := new(Token)
+	if err := awx.apiPost(endpoint, payload, result); err != nil {
+		return err
+	}
+
+	d.SetId(strconv.Itoa(result.ID))
+	// The token is only disclosed in the answer to the creation
+	d.Set("token", result.Token)
+	if result.RefreshToken != nil {
+		d.Set("refresh_token", *result.RefreshToken)
+	}
+	return resourceTokenRead(d, m)
+}
+
+func resourceTokenUpdate(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	if err := awx.apiPatch(fmt.Sprintf("/api/v2/tokens/%s/", d.Id()), tokenPayload(d), nil); err != nil {
+		return err
+	}
+	return resourceTokenRead(d, m)
+}
+
+func resourceTokenRead(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	result := new(Token)
+	if err := awx.apiGet(fmt.Sprintf("/api/v2/tokens/%s/", d.Id()), result, nil); err != nil {
+		if isNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	d = setTokenResourceData(d, result)
+	return nil
+}
+
+// resourceTokenDelete revokes the token.
+func resourceTokenDelete(d *schema.ResourceData, m interface{}) error {
+	awx := m.(*Client)
+	if err := awx.apiDelete(fmt.Sprintf("/api/v2/tokens/%s/", d.Id())); err != nil && !isNotFound(err) {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
+
+func tokenPayload(d *schema.ResourceData) map[string]interface{} {
+	return map[string]interface{}{
+		"description": d.Get("description").(string),
+		"scope":       d.Get("scope").(string),
+	}
+}
+
+func setTokenResourceData(d *schema.ResourceData, r *Token) *schema.ResourceData {
+	d.Set("user_id", r.User)
+	d.Set("application_id", 0)
+	if r.Application != nil {
+		d.Set("application_id", *r.Application)
+	}
+	d.Set("scope", r.Scope)
+	d.Set("description", r.Description)
+	d.Set("expires", r.Expires)
+	return d
+}
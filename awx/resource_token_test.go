@@ -0,0 +1,67 @@
+package awx
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/resource"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+// awx_token test case
+func TestAccAWXToken(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck:  func() { TestAccPreCheck(t) },
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccTokenConfig,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckStateToken("awx_token.testacc-pat_1", "scope", "read"),
+					testAccCheckStateToken("awx_token.testacc-pat_1", "application_id", "0"),
+					testAccCheckStateToken("awx_token.testacc-app-token_1", "scope", "write"),
+					resource.TestCheckResourceAttrSet("awx_token.testacc-pat_1", "token"),
+					resource.TestCheckResourceAttrSet("awx_token.testacc-app-token_1", "refresh_token"),
+				),
+			},
+		},
+	})
+}
+
+func testAccCheckStateToken(name, skey, svalue string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[name]
+		if !ok {
+			return fmt.Errorf("%s not found", name)
+		}
+		if rs.Primary.ID == "" {
+			return fmt.Errorf("No ID is set")
+		}
+
+		cr := rs.Primary
+
+		if cr.Attributes[skey] != svalue {
+			return fmt.Errorf("%s != %s (actual: %s)", skey, svalue, cr.Attributes[skey])
+		}
+
+		return nil
+	}
+}
+
+const testAccTokenConfig = `
+resource "awx_application" "testacc-app_1" {
+	name                     = "testacc-app_1"
+	organization_id          = 1
+	authorization_grant_type = "password"
+	client_type              = "public"
+}
+
+resource "awx_token" "testacc-pat_1" {
+	description = "testacc personal access token"
+	scope       = "read"
+}
+
+resource "awx_token" "testacc-app-token_1" {
+	application_id = "${awx_application.testacc-app_1.id}"
+}
+`
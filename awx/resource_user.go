@@ -3,18 +3,20 @@ package awx
 import (
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
-	awxgo "github.com/davidfischer-ch/awx-go"
 	"github.com/hashicorp/terraform/helper/schema"
+	"golang.org/x/crypto/bcrypt"
 )
 
 func resourceUserObject() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceUserCreate,
-		Read:   resourceUserRead,
-		Delete: resourceUserDelete,
-		Update: resourceUserUpdate,
+		Create:        resourceUserCreate,
+		Read:          resourceUserRead,
+		Delete:        resourceUserDelete,
+		Update:        resourceUserUpdate,
+		CustomizeDiff: resourceUserCustomizeDiff,
 
 		Schema: map[string]*schema.Schema{
 			"username": &schema.Schema{
@@ -24,9 +26,18 @@ func resourceUserObject() *schema.Resource {
 			},
 
 			"password": &schema.Schema{
+				Type:             schema.TypeString,
+				Optional:         true,
+				Sensitive:        true,
+				DiffSuppressFunc: suppressSamePassword,
+				Description:      "Password of this user, only its salted bcrypt hash is stored in the state. Required to create a local user, leave it unset for LDAP/SAML/social users.",
+			},
+
+			"password_version": &schema.Schema{
 				Type:        schema.TypeString,
-				Required:    true,
-				Description: "Password of this user.",
+				Optional:    true,
+				Default:     "",
+				Description: "Change this value to send the password again (e.g. after it was changed in AWX).",
 			},
 
 			"email": &schema.Schema{
@@ -61,6 +72,39 @@ func resourceUserObject() *schema.Resource {
 				Default:     false,
 				Description: "The user is a system administrator.",
 			},
+
+			"ldap_dn": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Distinguished name of the user in the LDAP directory it comes from.",
+			},
+			"external_account": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Where the account comes from (e.g. ldap, social, enterprise), empty for local users.",
+			},
+			"last_login": &schema.Schema{
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Date and time of the last login of the user.",
+			},
+			"auth": &schema.Schema{
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Social authentication backends the user is linked to.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"provider": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"uid": &schema.Schema{
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
 		},
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
@@ -75,7 +119,7 @@ func resourceUserObject() *schema.Resource {
 }
 
 func resourceUserCreate(d *schema.ResourceData, m interface{}) error {
-	awx := m.(*awxgo.AWX)
+	awx := m.(*Client)
 	awxService := awx.UserService
 	_, res, err := awxService.ListUsers(map[string]string{
 		"username": d.Get("username").(string)})
@@ -86,26 +130,27 @@ func resourceUserCreate(d *schema.ResourceData, m interface{}) error {
 		return fmt.Errorf("User with name %s already exists",
 			d.Get("username").(string))
 	}
+	if d.Get("password").(string) == "" {
+		return fmt.Errorf("A password is required to create user %s, external users (LDAP, SAML, ...) are created by AWX on their first login and may be imported instead",
+			d.Get("username").(string))
+	}
 
-	result, err := awxService.CreateUser(map[string]interface{}{
-		"username":          d.Get("username").(string),
-		"password":          d.Get("password").(string),
-		"email":             d.Get("email").(string),
-		"first_name":        d.Get("first_name").(string),
-		"last_name":         d.Get("last_name").(string),
-		"is_superuser":      d.Get("is_superuser").(bool),
-		"is_system_auditor": d.Get("is_system_auditor").(bool),
-	}, map[string]string{})
+	payload := userPayload(d)
+	payload["password"] = d.Get("password").(string)
+	result, err := awxService.CreateUser(payload, map[string]string{})
 	if err != nil {
 		return err
 	}
 
 	d.SetId(strconv.Itoa(result.ID))
+	if err := setPasswordHash(d); err != nil {
+		return err
+	}
 	return resourceUserRead(d, m)
 }
 
 func resourceUserUpdate(d *schema.ResourceData, m interface{}) error {
-	awx := m.(*awxgo.AWX)
+	awx := m.(*Client)
 	awxService := awx.UserService
 	_, res, err := awxService.ListUsers(map[string]string{
 		"id": d.Id()},
@@ -121,67 +166,149 @@ func resourceUserUpdate(d *schema.ResourceData, m interface{}) error {
 	if err != nil {
 		return err
 	}
-	_, err = awxService.UpdateUser(id, map[string]interface{}{
-		"username":          d.Get("username").(string),
-		"password":          d.Get("password").(string),
-		"email":             d.Get("email").(string),
-		"first_name":        d.Get("first_name").(string),
-		"last_name":         d.Get("last_name").(string),
-		"is_superuser":      d.Get("is_superuser").(bool),
-		"is_system_auditor": d.Get("is_system_auditor").(bool),
-	}, map[string]string{})
-	if err != nil {
+
+	// The password is only sent when asked to, AWX would otherwise reset it on every update
+	payload := userPayload(d)
+	if password := d.Get("password").(string); password != "" && (d.HasChange("password") || d.HasChange("password_version")) {
+		payload["password"] = password
+	}
+	if _, err = awxService.UpdateUser(id, payload, map[string]string{}); err != nil {
 		return err
 	}
+	if d.HasChange("password") {
+		if err := setPasswordHash(d); err != nil {
+			return err
+		}
+	}
 
 	return resourceUserRead(d, m)
 }
 
 func resourceUserRead(d *schema.ResourceData, m interface{}) error {
-	awx := m.(*awxgo.AWX)
-	awxService := awx.UserService
-	_, res, err := awxService.ListUsers(map[string]string{
-		"username": d.Get("username").(string)})
-	if err != nil {
+	awx := m.(*Client)
+	result := new(User)
+	if err := awx.apiGet(fmt.Sprintf("/api/v2/users/%s/", d.Id()), result, nil); err != nil {
+		if isNotFound(err) {
+			d.SetId("")
+			return nil
+		}
 		return err
 	}
-	if len(res.Results) == 0 {
-		return nil
-	}
-	d = setUserResourceData(d, res.Results[0])
+	d = setUserResourceData(d, result)
 	return nil
 }
 
 func resourceUserDelete(d *schema.ResourceData, m interface{}) error {
-	awx := m.(*awxgo.AWX)
+	awx := m.(*Client)
 	awxService := awx.UserService
 	id, err := strconv.Atoi(d.Id())
 	if err != nil {
 		return err
 	}
-	_, res, err := awxService.ListUsers(map[string]string{
-		"username": d.Get("username").(string)})
-	if len(res.Results) == 0 {
-		d.SetId("")
+	result := new(User)
+	if err := awx.apiGet(fmt.Sprintf("/api/v2/users/%d/", id), result, nil); err != nil {
+		if isNotFound(err) {
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	if result.IsSuperuser {
+		if err := awx.ensureNotLastSuperuser(id, "delete"); err != nil {
+			return err
+		}
+	}
+	if _, err = awxService.DeleteUser(id); err != nil {
+		return err
+	}
+	d.SetId("")
+	return nil
+}
+
+// resourceUserCustomizeDiff refuses to plan the demotion of the last superuser.
+func resourceUserCustomizeDiff(d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() == "" || !d.HasChange("is_superuser") || d.Get("is_superuser").(bool) {
 		return nil
 	}
+	id, err := strconv.Atoi(d.Id())
 	if err != nil {
 		return err
 	}
-	if _, err = awxService.DeleteUser(id); err != nil {
+	return m.(*Client).ensureNotLastSuperuser(id, "demote")
+}
+
+// ensureNotLastSuperuser returns an error if user id is the only remaining superuser, so nobody is locked out.
+func (c *Client) ensureNotLastSuperuser(id int, action string) error {
+	var superusers []User
+	if err := c.apiList("/api/v2/users/", map[string]string{"is_superuser": "true"}, &superusers); err != nil {
 		return err
 	}
-	d.SetId("")
+	for _, user := range superusers {
+		if user.ID != id {
+			return nil
+		}
+	}
+	return fmt.Errorf("Refusing to %s user %d, it is the last superuser of AWX", action, id)
+}
+
+// setPasswordHash replaces the password by its bcrypt hash in the state, the salt makes it costly to brute-force.
+func setPasswordHash(d *schema.ResourceData) error {
+	password := d.Get("password").(string)
+	if password == "" {
+		d.Set("password", "")
+		return nil
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	d.Set("password", string(hash))
 	return nil
 }
 
-func setUserResourceData(d *schema.ResourceData, r *awxgo.User) *schema.ResourceData {
+// suppressSamePassword ignores a password matching the bcrypt hash stored in the state, unless password_version
+// changes: the password must then be in the diff to be sent again.
+func suppressSamePassword(k, old, new string, d *schema.ResourceData) bool {
+	if old == new {
+		return true
+	}
+	if new == "" || d.HasChange("password_version") || !strings.HasPrefix(old, "$2") {
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(old), []byte(new)) == nil
+}
+
+func userPayload(d *schema.ResourceData) map[string]interface{} {
+	return map[string]interface{}{
+		"username":          d.Get("username").(string),
+		"email":             d.Get("email").(string),
+		"first_name":        d.Get("first_name").(string),
+		"last_name":         d.Get("last_name").(string),
+		"is_superuser":      d.Get("is_superuser").(bool),
+		"is_system_auditor": d.Get("is_system_auditor").(bool),
+	}
+}
+
+func setUserResourceData(d *schema.ResourceData, r *User) *schema.ResourceData {
 	d.Set("username", r.Username)
-	d.Set("password", d.Get("password").(string))
 	d.Set("email", r.Email)
 	d.Set("first_name", r.FirstName)
 	d.Set("last_name", r.LastName)
-	d.Set("is_superuser", r.IsSuperUser)
+	d.Set("is_superuser", r.IsSuperuser)
 	d.Set("is_system_auditor", r.IsSystemAuditor)
+	d.Set("ldap_dn", r.LdapDn)
+	d.Set("external_account", "")
+	if r.ExternalAccount != nil {
+		d.Set("external_account", *r.ExternalAccount)
+	}
+	d.Set("last_login", "")
+	if r.LastLogin != nil {
+		d.Set("last_login", *r.LastLogin)
+	}
+	auth := make([]interface{}, 0, len(r.Auth))
+	for _, a := range r.Auth {
+		auth = append(auth, map[string]interface{}{"provider": a["provider"], "uid": a["uid"]})
+	}
+	d.Set("auth", auth)
 	return d
 }
@@ -1,11 +1,19 @@
 package awx
 
 import (
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
 
+	awxgo "github.com/davidfischer-ch/awx-go"
+
 	"github.com/hashicorp/terraform/helper/resource"
 	"github.com/hashicorp/terraform/terraform"
+	"golang.org/x/crypto/bcrypt"
 )
 
 // awx_user test case
@@ -18,17 +26,42 @@ func TestAccAWXUser(t *testing.T) {
 				Config: testAccUserConfig,
 				Check: resource.ComposeTestCheckFunc(
 					testAccCheckStateUser("username", "mauromedda"),
-					testAccCheckStateUser("password", "password"),
 					testAccCheckStateUser("first_name", "Mauro"),
 					testAccCheckStateUser("last_name", "Medda"),
 					testAccCheckStateUser("is_superuser", "true"),
 					testAccCheckStateUser("email", "medda.mauro@test.td"),
+					testAccCheckStateUser("external_account", ""),
 				),
 			},
 		},
 	})
 }
 
+// ensureNotLastSuperuser test case, against a local stub of the users endpoint
+func TestEnsureNotLastSuperuser(t *testing.T) {
+	superusers := []map[string]interface{}{{"id": 1, "username": "admin", "is_superuser": true}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v2/users/" || r.URL.Query().Get("is_superuser") != "true" {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"count": len(superusers), "next": nil, "results": superusers})
+	}))
+	defer server.Close()
+	client := &Client{Requester: &awxgo.Requester{Base: server.URL, Client: server.Client()}}
+
+	if err := client.ensureNotLastSuperuser(1, "delete"); err == nil {
+		t.Error("Expected an error when deleting the last superuser")
+	}
+	if err := client.ensureNotLastSuperuser(2, "delete"); err != nil {
+		t.Errorf("Unexpected error when the user is not a superuser: %s", err)
+	}
+	superusers = append(superusers, map[string]interface{}{"id": 2, "username": "ops", "is_superuser": true})
+	if err := client.ensureNotLastSuperuser(1, "demote"); err != nil {
+		t.Errorf("Unexpected error when another superuser remains: %s", err)
+	}
+}
+
 func testAccCheckStateUser(skey, svalue string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources["awx_user.testacc-user_1"]
@@ -59,3 +92,101 @@ resource "awx_user" "testacc-user_1" {
 	email = "medda.mauro@test.td"
   }
 `
+
+// awx_user password handling, against a local stub of the users endpoints
+func TestAWXUserPassword(t *testing.T) {
+	var passwords []string
+	user := map[string]interface{}{"id": 1, "username": "ops", "email": "ops@example.com"}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]interface{}
+		if r.Method == "POST" || r.Method == "PATCH" {
+			json.NewDecoder(r.Body).Decode(&body)
+			if password, ok := body["password"]; ok {
+				passwords = append(passwords, password.(string))
+			}
+			delete(body, "password")
+		}
+		switch strings.TrimSuffix(r.URL.Path, "/") {
+		case "/api/v2/users":
+			if r.Method == "POST" {
+				w.WriteHeader(http.StatusCreated)
+				json.NewEncoder(w).Encode(user)
+				return
+			}
+			results := []interface{}{}
+			if r.URL.Query().Get("id") == "1" {
+				results = append(results, user)
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"count": len(results), "next": nil, "results": results})
+		case "/api/v2/users/1":
+			for key, value := range body {
+				user[key] = value
+			}
+			json.NewEncoder(w).Encode(user)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	provider := fmt.Sprintf("provider \"awx\" {\n\tendpoint = %q\n}\n", server.URL)
+
+	resource.UnitTest(t, resource.TestCase{
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: provider + fmt.Sprintf(testAWXUserPasswordConfig, "s3cret", ""),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckUserPasswordHash("s3cret"),
+					testCheckUserPasswordsSent(&passwords, "s3cret"),
+				),
+			},
+			{
+				// The password only is sent again when it or password_version changes
+				Config: provider + fmt.Sprintf(testAWXUserPasswordConfig, "s3cret", ""),
+				Check:  testCheckUserPasswordsSent(&passwords, "s3cret"),
+			},
+			{
+				Config: provider + fmt.Sprintf(testAWXUserPasswordConfig, "s3cret", "2"),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckUserPasswordHash("s3cret"),
+					testCheckUserPasswordsSent(&passwords, "s3cret", "s3cret"),
+				),
+			},
+			{
+				Config: provider + fmt.Sprintf(testAWXUserPasswordConfig, "n3w", "2"),
+				Check: resource.ComposeTestCheckFunc(
+					testCheckUserPasswordHash("n3w"),
+					testCheckUserPasswordsSent(&passwords, "s3cret", "s3cret", "n3w"),
+				),
+			},
+		},
+	})
+}
+
+func testCheckUserPasswordHash(password string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		hash := s.RootModule().Resources["awx_user.ops"].Primary.Attributes["password"]
+		if err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)); err != nil {
+			return fmt.Errorf("Password in the state %q is not the hash of %q: %s", hash, password, err)
+		}
+		return nil
+	}
+}
+
+func testCheckUserPasswordsSent(passwords *[]string, expected ...string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		if !reflect.DeepEqual(*passwords, expected) {
+			return fmt.Errorf("Passwords sent are %v, expected %v", *passwords, expected)
+		}
+		return nil
+	}
+}
+
+const testAWXUserPasswordConfig = `
+resource "awx_user" "ops" {
+	username         = "ops"
+	email            = "ops@example.com"
+	password         = %q
+	password_version = %q
+}
+`
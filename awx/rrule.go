@@ -0,0 +1,522 @@
+package awx
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	rruleDateTimeFormat    = "20060102T150405"
+	rruleDateTimeUTCFormat = "20060102T150405Z"
+	rruleDateFormat        = "20060102"
+
+	// Upper bound of periods walked while looking for occurrences (one year of MINUTELY periods)
+	rruleMaxPeriods = 527040
+)
+
+var rruleFrequencies = []string{"MINUTELY", "HOURLY", "DAILY", "WEEKLY", "MONTHLY", "YEARLY"}
+
+var rruleWeekdays = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+// rrule is the subset of RFC 5545 recurrence rules accepted by AWX schedules.
+type rrule struct {
+	dtstart    time.Time
+	freq       string
+	interval   int
+	count      int
+	until      time.Time
+	byDay      []time.Weekday
+	byMonthDay []int
+	byMonth    []int
+	byHour     []int
+	byMinute   []int
+	bySetPos   []int
+	wkst       time.Weekday
+}
+
+// parseRRule parses a schedule rule as AWX expects it: "DTSTART;TZID=Europe/Zurich:20200101T120000 RRULE:FREQ=DAILY;INTERVAL=1".
+// It rejects the same constructs AWX rejects so errors are reported at plan time.
+func parseRRule(s string) (*rrule, error) {
+	var dtstart, rule string
+	for _, field := range strings.Fields(s) {
+		switch {
+		case strings.HasPrefix(field, "DTSTART"):
+			if dtstart != "" {
+				return nil, fmt.Errorf("Multiple DTSTART is not supported")
+			}
+			dtstart = field
+		case strings.HasPrefix(field, "RRULE:"):
+			if rule != "" {
+				return nil, fmt.Errorf("Multiple RRULE is not supported")
+			}
+			rule = strings.TrimPrefix(field, "RRULE:")
+		default:
+			return nil, fmt.Errorf("Unsupported rrule component %q", field)
+		}
+	}
+	if dtstart == "" {
+		return nil, fmt.Errorf("Valid DTSTART required in rrule, value should start with: DTSTART:YYYYMMDDTHHMMSSZ")
+	}
+	if rule == "" {
+		return nil, fmt.Errorf("RRULE required in rrule")
+	}
+
+	r := &rrule{interval: 1, wkst: time.Monday}
+	start, err := parseRRuleDTStart(dtstart)
+	if err != nil {
+		return nil, err
+	}
+	r.dtstart = start
+
+	hasInterval := false
+	for _, part := range strings.Split(rule, ";") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 || kv[1] == "" {
+			return nil, fmt.Errorf("Invalid RRULE part %q", part)
+		}
+		key, value := strings.ToUpper(kv[0]), strings.ToUpper(kv[1])
+		switch key {
+		case "FREQ":
+			if value == "SECONDLY" {
+				return nil, fmt.Errorf("SECONDLY is not supported")
+			}
+			if !stringInSlice(value, rruleFrequencies) {
+				return nil, fmt.Errorf("Invalid FREQ %q, must be one of %s", value, strings.Join(rruleFrequencies, ", "))
+			}
+			r.freq = value
+		case "INTERVAL":
+			if r.interval, err = strconv.Atoi(value); err != nil || r.interval < 1 {
+				return nil, fmt.Errorf("INTERVAL must be a positive integer, got %q", value)
+			}
+			hasInterval = true
+		case "COUNT":
+			if r.count, err = strconv.Atoi(value); err != nil || r.count < 1 {
+				return nil, fmt.Errorf("COUNT must be a positive integer, got %q", value)
+			}
+			if r.count > 999 {
+				return nil, fmt.Errorf("COUNT > 999 is unsupported")
+			}
+		case "UNTIL":
+			if r.until, err = parseRRuleUntil(value, start.Location()); err != nil {
+				return nil, err
+			}
+		case "BYDAY":
+			for _, day := range strings.Split(value, ",") {
+				weekday, ok := rruleWeekdays[day]
+				if !ok {
+					if len(day) > 2 {
+						if _, ok := rruleWeekdays[day[len(day)-2:]]; ok {
+							return nil, fmt.Errorf("BYDAY with numeric prefix not supported, use BYSETPOS instead")
+						}
+					}
+					return nil, fmt.Errorf("Invalid BYDAY value %q", day)
+				}
+				r.byDay = append(r.byDay, weekday)
+			}
+		case "BYMONTHDAY":
+			if r.byMonthDay, err = parseRRuleInts(key, value, -31, 31); err != nil {
+				return nil, err
+			}
+			if len(r.byMonthDay) > 1 {
+				return nil, fmt.Errorf("Multiple BYMONTHDAYs not supported")
+			}
+		case "BYMONTH":
+			if r.byMonth, err = parseRRuleInts(key, value, 1, 12); err != nil {
+				return nil, err
+			}
+			if len(r.byMonth) > 1 {
+				return nil, fmt.Errorf("Multiple BYMONTHs not supported")
+			}
+		case "BYHOUR":
+			if r.byHour, err = parseRRuleInts(key, value, 0, 23); err != nil {
+				return nil, err
+			}
+		case "BYMINUTE":
+			if r.byMinute, err = parseRRuleInts(key, value, 0, 59); err != nil {
+				return nil, err
+			}
+		case "BYSETPOS":
+			if r.bySetPos, err = parseRRuleInts(key, value, -366, 366); err != nil {
+				return nil, err
+			}
+		case "WKST":
+			weekday, ok := rruleWeekdays[value]
+			if !ok {
+				return nil, fmt.Errorf("Invalid WKST value %q", value)
+			}
+			r.wkst = weekday
+		case "BYYEARDAY", "BYWEEKNO", "BYSECOND":
+			return nil, fmt.Errorf("%s not supported", key)
+		default:
+			return nil, fmt.Errorf("Unknown RRULE part %q", key)
+		}
+	}
+
+	if r.freq == "" {
+		return nil, fmt.Errorf("FREQ required in rrule")
+	}
+	if !hasInterval {
+		return nil, fmt.Errorf("INTERVAL required in rrule")
+	}
+	if r.count != 0 && !r.until.IsZero() {
+		return nil, fmt.Errorf("RRULE may not contain both COUNT and UNTIL")
+	}
+	if !r.until.IsZero() && r.until.Before(r.dtstart) {
+		return nil, fmt.Errorf("UNTIL must not be before DTSTART")
+	}
+	return r, nil
+}
+
+func parseRRuleDTStart(s string) (time.Time, error) {
+	parts := strings.SplitN(s, ":", 2)
+	if len(parts) != 2 {
+		return time.Time{}, fmt.Errorf("Invalid DTSTART %q", s)
+	}
+	params, value := parts[0], parts[1]
+	if params == "DTSTART" {
+		if !strings.HasSuffix(value, "Z") {
+			return time.Time{}, fmt.Errorf("DTSTART cannot be a naive datetime, specify ;TZID= or YYYYMMDDTHHMMSSZ")
+		}
+		t, err := time.Parse(rruleDateTimeUTCFormat, value)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("Invalid DTSTART %q: %s", value, err)
+		}
+		return t, nil
+	}
+	if !strings.HasPrefix(params, "DTSTART;TZID=") {
+		return time.Time{}, fmt.Errorf("Invalid DTSTART parameters %q", params)
+	}
+	loc, err := loadTimezone(strings.TrimPrefix(params, "DTSTART;TZID="))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("Invalid DTSTART timezone: %s", err)
+	}
+	t, err := time.ParseInLocation(rruleDateTimeFormat, value, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("Invalid DTSTART %q: %s", value, err)
+	}
+	return t, nil
+}
+
+// loadTimezone loads a zone of the tz database of the system running Terraform, the database is not embedded in the
+// provider (set ZONEINFO to use another one).
+func loadTimezone(name string) (*time.Location, error) {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("Unknown timezone %q, it is missing from the tz database of this system (%s)", name, err)
+	}
+	return loc, nil
+}
+
+func parseRRuleUntil(s string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(rruleDateTimeUTCFormat, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation(rruleDateTimeFormat, s, loc); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation(rruleDateFormat, s, loc); err == nil {
+		return t.Add(24*time.Hour - time.Second), nil
+	}
+	return time.Time{}, fmt.Errorf("Invalid UNTIL %q, expected YYYYMMDDTHHMMSSZ", s)
+}
+
+func parseRRuleInts(key, value string, min, max int) ([]int, error) {
+	var values []int
+	for _, raw := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < min || n > max || (n == 0 && min < 0) {
+			return nil, fmt.Errorf("Invalid %s value %q", key, raw)
+		}
+		values = append(values, n)
+	}
+	return values, nil
+}
+
+// occurrences returns up to n occurrences of the rule strictly after the given time.
+func (r *rrule) occurrences(after time.Time, n int) []time.Time {
+	var result []time.Time
+	if n <= 0 {
+		return result
+	}
+	emitted := 0
+	first := r.firstPeriod(after)
+	for period := first; period < first+rruleMaxPeriods; period++ {
+		for _, t := range r.applySetPos(r.candidates(period)) {
+			if t.Before(r.dtstart) {
+				continue
+			}
+			if !r.until.IsZero() && t.After(r.until) {
+				return result
+			}
+			emitted++
+			if t.After(after) {
+				result = append(result, t)
+				if len(result) == n {
+					return result
+				}
+			}
+			if r.count != 0 && emitted == r.count {
+				return result
+			}
+		}
+	}
+	return result
+}
+
+// firstPeriod skips the periods entirely before after, unless COUNT requires walking them.
+func (r *rrule) firstPeriod(after time.Time) int {
+	if r.count != 0 || !after.After(r.dtstart) {
+		return 0
+	}
+	start, elapsed := r.dtstart, after.Sub(r.dtstart)
+	var periods int
+	switch r.freq {
+	case "MINUTELY":
+		periods = int(elapsed / time.Minute)
+	case "HOURLY":
+		periods = int(elapsed / time.Hour)
+	case "DAILY":
+		periods = int(elapsed / (24 * time.Hour))
+	case "WEEKLY":
+		periods = int(elapsed / (7 * 24 * time.Hour))
+	case "MONTHLY":
+		periods = (after.Year()-start.Year())*12 + int(after.Month()) - int(start.Month())
+	case "YEARLY":
+		periods = after.Year() - start.Year()
+	}
+	if periods = periods/r.interval - 1; periods < 0 {
+		return 0
+	}
+	return periods
+}
+
+// candidates expands the period-th period of the rule into its sorted instances.
+func (r *rrule) candidates(period int) []time.Time {
+	loc := r.dtstart.Location()
+	start := r.dtstart
+	step := period * r.interval
+	var days []time.Time
+
+	switch r.freq {
+	case "YEARLY":
+		year := start.Year() + step
+		months := r.byMonth
+		if len(months) == 0 {
+			if len(r.byDay) > 0 || len(r.byMonthDay) > 0 {
+				months = []int{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
+			} else {
+				months = []int{int(start.Month())}
+			}
+		}
+		for _, month := range months {
+			days = append(days, r.monthDays(year, time.Month(month))...)
+		}
+	case "MONTHLY":
+		first := time.Date(start.Year(), start.Month()+time.Month(step), 1, 0, 0, 0, 0, loc)
+		if r.matchMonth(first) {
+			days = r.monthDays(first.Year(), first.Month())
+		}
+	case "WEEKLY":
+		offset := (int(start.Weekday()) - int(r.wkst) + 7) % 7
+		weekStart := time.Date(start.Year(), start.Month(), start.Day()-offset+7*step, 0, 0, 0, 0, loc)
+		for i := 0; i < 7; i++ {
+			day := time.Date(weekStart.Year(), weekStart.Month(), weekStart.Day()+i, 0, 0, 0, 0, loc)
+			if !r.matchMonth(day) {
+				continue
+			}
+			if len(r.byDay) > 0 && !weekdayInSlice(day.Weekday(), r.byDay) {
+				continue
+			}
+			if len(r.byDay) == 0 && day.Weekday() != start.Weekday() {
+				continue
+			}
+			days = append(days, day)
+		}
+	case "DAILY":
+		day := time.Date(start.Year(), start.Month(), start.Day()+step, 0, 0, 0, 0, loc)
+		if r.matchDay(day) {
+			days = append(days, day)
+		}
+	case "HOURLY", "MINUTELY":
+		unit := time.Hour
+		if r.freq == "MINUTELY" {
+			unit = time.Minute
+		}
+		t := start.Truncate(unit).Add(time.Duration(step) * unit).In(loc)
+		if !r.matchDay(t) || (len(r.byHour) > 0 && !intInSlice(t.Hour(), r.byHour)) {
+			return nil
+		}
+		if r.freq == "MINUTELY" {
+			if len(r.byMinute) > 0 && !intInSlice(t.Minute(), r.byMinute) {
+				return nil
+			}
+			return []time.Time{time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), start.Second(), 0, loc)}
+		}
+		minutes := r.byMinute
+		if len(minutes) == 0 {
+			minutes = []int{start.Minute()}
+		}
+		var result []time.Time
+		for _, minute := range minutes {
+			result = append(result, time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), minute, start.Second(), 0, loc))
+		}
+		sortTimes(result)
+		return result
+	}
+
+	hours := r.byHour
+	if len(hours) == 0 {
+		hours = []int{start.Hour()}
+	}
+	minutes := r.byMinute
+	if len(minutes) == 0 {
+		minutes = []int{start.Minute()}
+	}
+	var result []time.Time
+	for _, day := range days {
+		for _, hour := range hours {
+			for _, minute := range minutes {
+				result = append(result, time.Date(day.Year(), day.Month(), day.Day(), hour, minute, start.Second(), 0, loc))
+			}
+		}
+	}
+	sortTimes(result)
+	return result
+}
+
+// monthDays returns the days of the month selected by BYMONTHDAY and BYDAY (DTSTART's day if none is set).
+func (r *rrule) monthDays(year int, month time.Month) []time.Time {
+	loc := r.dtstart.Location()
+	last := time.Date(year, month+1, 0, 0, 0, 0, 0, loc).Day()
+	var days []time.Time
+	if len(r.byMonthDay) == 0 && len(r.byDay) == 0 {
+		if r.dtstart.Day() <= last {
+			days = append(days, time.Date(year, month, r.dtstart.Day(), 0, 0, 0, 0, loc))
+		}
+		return days
+	}
+	for d := 1; d <= last; d++ {
+		day := time.Date(year, month, d, 0, 0, 0, 0, loc)
+		if r.matchDay(day) {
+			days = append(days, day)
+		}
+	}
+	return days
+}
+
+func (r *rrule) matchMonth(t time.Time) bool {
+	return len(r.byMonth) == 0 || intInSlice(int(t.Month()), r.byMonth)
+}
+
+func (r *rrule) matchDay(t time.Time) bool {
+	if !r.matchMonth(t) {
+		return false
+	}
+	if len(r.byDay) > 0 && !weekdayInSlice(t.Weekday(), r.byDay) {
+		return false
+	}
+	if len(r.byMonthDay) > 0 {
+		last := time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, t.Location()).Day()
+		matched := false
+		for _, d := range r.byMonthDay {
+			if d == t.Day() || (d < 0 && last+d+1 == t.Day()) {
+				matched = true
+			}
+		}
+		return matched
+	}
+	return true
+}
+
+func (r *rrule) applySetPos(set []time.Time) []time.Time {
+	if len(r.bySetPos) == 0 || len(set) == 0 {
+		return set
+	}
+	var result []time.Time
+	for _, pos := range r.bySetPos {
+		i := pos - 1
+		if pos < 0 {
+			i = len(set) + pos
+		}
+		if i >= 0 && i < len(set) {
+			result = append(result, set[i])
+		}
+	}
+	sortTimes(result)
+	return result
+}
+
+// buildRRule renders the structured schedule fields as an AWX rrule.
+func buildRRule(start time.Time, frequency string, interval int, byDay []string, until time.Time, count int) string {
+	var dtstart string
+	if start.Location() == time.UTC {
+		dtstart = "DTSTART:" + start.Format(rruleDateTimeUTCFormat)
+	} else {
+		dtstart = fmt.Sprintf("DTSTART;TZID=%s:%s", start.Location(), start.Format(rruleDateTimeFormat))
+	}
+	parts := []string{"FREQ=" + frequency, "INTERVAL=" + strconv.Itoa(interval)}
+	if len(byDay) > 0 {
+		parts = append(parts, "BYDAY="+strings.Join(byDay, ","))
+	}
+	if !until.IsZero() {
+		parts = append(parts, "UNTIL="+until.UTC().Format(rruleDateTimeUTCFormat))
+	}
+	if count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(count))
+	}
+	return dtstart + " RRULE:" + strings.Join(parts, ";")
+}
+
+// parseScheduleTime accepts an RFC 3339 timestamp or a naive "2006-01-02T15:04:05" local to loc.
+func parseScheduleTime(s string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.In(loc), nil
+	}
+	t, err := time.ParseInLocation("2006-01-02T15:04:05", s, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("Invalid time %q, expected RFC 3339 or YYYY-MM-DDTHH:MM:SS", s)
+	}
+	return t, nil
+}
+
+func sortTimes(times []time.Time) {
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+}
+
+func stringInSlice(s string, slice []string) bool {
+	for _, v := range slice {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func intInSlice(n int, slice []int) bool {
+	for _, v := range slice {
+		if v == n {
+			return true
+		}
+	}
+	return false
+}
+
+func weekdayInSlice(d time.Weekday, slice []time.Weekday) bool {
+	for _, v := range slice {
+		if v == d {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,145 @@
+package awx
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseRRuleErrors(t *testing.T) {
+	cases := map[string]string{
+		"RRULE:FREQ=DAILY;INTERVAL=1":                                                         "DTSTART required",
+		"DTSTART:20200101T120000Z":                                                            "RRULE required",
+		"DTSTART:20200101T120000 RRULE:FREQ=DAILY;INTERVAL=1":                                 "naive datetime",
+		"DTSTART;TZID=Mars/Olympus:20200101T120000 RRULE:FREQ=DAILY;INTERVAL=1":               "timezone",
+		"DTSTART:20200101T120000Z RRULE:FREQ=DAILY":                                           "INTERVAL required",
+		"DTSTART:20200101T120000Z RRULE:INTERVAL=1":                                           "FREQ required",
+		"DTSTART:20200101T120000Z RRULE:FREQ=SECONDLY;INTERVAL=1":                             "SECONDLY",
+		"DTSTART:20200101T120000Z RRULE:FREQ=DAILY;INTERVAL=0":                                "INTERVAL must be",
+		"DTSTART:20200101T120000Z RRULE:FREQ=DAILY;INTERVAL=1;COUNT=1000":                     "COUNT > 999",
+		"DTSTART:20200101T120000Z RRULE:FREQ=DAILY;INTERVAL=1;COUNT=2;UNTIL=20200201T000000Z": "both COUNT and UNTIL",
+		"DTSTART:20200101T120000Z RRULE:FREQ=MONTHLY;INTERVAL=1;BYDAY=1MO":                    "numeric prefix",
+		"DTSTART:20200101T120000Z RRULE:FREQ=WEEKLY;INTERVAL=1;BYDAY=XX":                      "Invalid BYDAY",
+		"DTSTART:20200101T120000Z RRULE:FREQ=MONTHLY;INTERVAL=1;BYMONTHDAY=1,15":              "Multiple BYMONTHDAYs",
+		"DTSTART:20200101T120000Z RRULE:FREQ=YEARLY;INTERVAL=1;BYWEEKNO=1":                    "BYWEEKNO not supported",
+		"DTSTART:20200101T120000Z RRULE:FREQ=DAILY;INTERVAL=1;BYHOUR=24":                      "Invalid BYHOUR",
+		"DTSTART:20200101T120000Z RRULE:FREQ=DAILY;INTERVAL=1;FOO=1":                          "Unknown RRULE part",
+		"DTSTART:20200101T120000Z DTSTART:20200102T120000Z RRULE:FREQ=DAILY;INTERVAL=1":       "Multiple DTSTART",
+		"DTSTART:20200101T120000Z RRULE:FREQ=DAILY;INTERVAL=1;UNTIL=20191231T000000Z":         "UNTIL must not be before",
+	}
+	for rule, expected := range cases {
+		_, err := parseRRule(rule)
+		if err == nil {
+			t.Errorf("%q: expected an error containing %q", rule, expected)
+			continue
+		}
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("%q: expected an error containing %q, got %q", rule, expected, err)
+		}
+	}
+}
+
+func TestRRuleOccurrences(t *testing.T) {
+	cases := []struct {
+		rule     string
+		after    string
+		n        int
+		expected []string
+	}{
+		{
+			"DTSTART:20200101T120000Z RRULE:FREQ=DAILY;INTERVAL=2;COUNT=3",
+			"2019-01-01T00:00:00Z", 5,
+			[]string{"2020-01-01T12:00:00Z", "2020-01-03T12:00:00Z", "2020-01-05T12:00:00Z"},
+		},
+		{
+			"DTSTART:20200101T120000Z RRULE:FREQ=DAILY;INTERVAL=1;COUNT=3",
+			"2020-01-01T12:00:00Z", 5,
+			[]string{"2020-01-02T12:00:00Z", "2020-01-03T12:00:00Z"},
+		},
+		{
+			// 2020-01-01 is a Wednesday
+			"DTSTART:20200101T080000Z RRULE:FREQ=WEEKLY;INTERVAL=1;BYDAY=MO,WE,FR",
+			"2019-01-01T00:00:00Z", 4,
+			[]string{"2020-01-01T08:00:00Z", "2020-01-03T08:00:00Z", "2020-01-06T08:00:00Z", "2020-01-08T08:00:00Z"},
+		},
+		{
+			"DTSTART:20200131T000000Z RRULE:FREQ=MONTHLY;INTERVAL=1",
+			"2019-01-01T00:00:00Z", 3,
+			[]string{"2020-01-31T00:00:00Z", "2020-03-31T00:00:00Z", "2020-05-31T00:00:00Z"},
+		},
+		{
+			// Last Friday of the month
+			"DTSTART:20200101T220000Z RRULE:FREQ=MONTHLY;INTERVAL=1;BYDAY=FR;BYSETPOS=-1",
+			"2019-01-01T00:00:00Z", 2,
+			[]string{"2020-01-31T22:00:00Z", "2020-02-28T22:00:00Z"},
+		},
+		{
+			"DTSTART:20200101T000000Z RRULE:FREQ=MONTHLY;INTERVAL=1;BYMONTHDAY=-1",
+			"2019-01-01T00:00:00Z", 2,
+			[]string{"2020-01-31T00:00:00Z", "2020-02-29T00:00:00Z"},
+		},
+		{
+			"DTSTART:20200229T060000Z RRULE:FREQ=YEARLY;INTERVAL=1",
+			"2019-01-01T00:00:00Z", 2,
+			[]string{"2020-02-29T06:00:00Z", "2024-02-29T06:00:00Z"},
+		},
+		{
+			"DTSTART:20200101T000000Z RRULE:FREQ=HOURLY;INTERVAL=6;BYMINUTE=0,30",
+			"2020-01-01T00:00:00Z", 3,
+			[]string{"2020-01-01T00:30:00Z", "2020-01-01T06:00:00Z", "2020-01-01T06:30:00Z"},
+		},
+		{
+			"DTSTART:20200101T000000Z RRULE:FREQ=MINUTELY;INTERVAL=15;UNTIL=20200101T003000Z",
+			"2019-01-01T00:00:00Z", 10,
+			[]string{"2020-01-01T00:00:00Z", "2020-01-01T00:15:00Z", "2020-01-01T00:30:00Z"},
+		},
+		{
+			// Daylight saving time starts on 2020-03-29 in Europe/Zurich
+			"DTSTART;TZID=Europe/Zurich:20200328T020000 RRULE:FREQ=DAILY;INTERVAL=1;COUNT=3",
+			"2019-01-01T00:00:00Z", 3,
+			[]string{"2020-03-28T02:00:00+01:00", "2020-03-29T03:00:00+02:00", "2020-03-30T02:00:00+02:00"},
+		},
+		{
+			// Skipping periods far in the past must not change the result
+			"DTSTART:20100101T000000Z RRULE:FREQ=MINUTELY;INTERVAL=7",
+			"2020-01-01T00:00:00Z", 2,
+			[]string{"2020-01-01T00:03:00Z", "2020-01-01T00:10:00Z"},
+		},
+	}
+	for _, c := range cases {
+		r, err := parseRRule(c.rule)
+		if err != nil {
+			t.Fatalf("%q: %s", c.rule, err)
+		}
+		after, _ := time.Parse(time.RFC3339, c.after)
+		var got []string
+		for _, o := range r.occurrences(after, c.n) {
+			got = append(got, o.Format(time.RFC3339))
+		}
+		if strings.Join(got, " ") != strings.Join(c.expected, " ") {
+			t.Errorf("%q after %s:\n got %v\nwant %v", c.rule, c.after, got, c.expected)
+		}
+	}
+}
+
+func TestBuildRRule(t *testing.T) {
+	zurich, _ := time.LoadLocation("Europe/Zurich")
+	start, _ := parseScheduleTime("2020-01-01T02:00:00", zurich)
+	until, _ := parseScheduleTime("2020-06-30T00:00:00", zurich)
+
+	rule := buildRRule(start, "WEEKLY", 2, []string{"MO", "TH"}, until, 0)
+	expected := "DTSTART;TZID=Europe/Zurich:20200101T020000 RRULE:FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH;UNTIL=20200629T220000Z"
+	if rule != expected {
+		t.Fatalf("got %q, want %q", rule, expected)
+	}
+	if _, err := parseRRule(rule); err != nil {
+		t.Fatalf("built rule does not parse: %s", err)
+	}
+
+	start, _ = parseScheduleTime("2020-01-01T02:00:00Z", time.UTC)
+	rule = buildRRule(start, "DAILY", 1, nil, time.Time{}, 10)
+	expected = "DTSTART:20200101T020000Z RRULE:FREQ=DAILY;INTERVAL=1;COUNT=10"
+	if rule != expected {
+		t.Fatalf("got %q, want %q", rule, expected)
+	}
+}
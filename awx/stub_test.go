@@ -0,0 +1,766 @@
+package awx
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// settingsStub is a local stand-in for the /api/v2/settings/<category>/ endpoints of AWX.
+type settingsStub struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	defaults map[string]map[string]interface{}
+	values   map[string]map[string]interface{}
+	secrets  map[string]bool
+}
+
+func newSettingsStub(t *testing.T, defaults map[string]map[string]interface{}, secrets ...string) *settingsStub {
+	stub := &settingsStub{
+		defaults: defaults,
+		values:   map[string]map[string]interface{}{},
+		secrets:  map[string]bool{},
+	}
+	for category, values := range defaults {
+		stub.values[category] = deepCopyJSON(t, values).(map[string]interface{})
+	}
+	for _, key := range secrets {
+		stub.secrets[key] = true
+	}
+	stub.Server = httptest.NewServer(http.HandlerFunc(stub.serve))
+	return stub
+}
+
+func (s *settingsStub) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	category := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v2/settings/"), "/")
+	values, ok := s.values[category]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		answer := map[string]interface{}{}
+		for k, v := range values {
+			if s.secrets[k] && v != "" {
+				v = encryptedValue
+			}
+			answer[k] = v
+		}
+		json.NewEncoder(w).Encode(answer)
+	case "PATCH":
+		var patch map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		for k, v := range patch {
+			if _, ok := values[k]; !ok {
+				http.Error(w, `{"`+k+`": ["Unknown setting"]}`, http.StatusBadRequest)
+				return
+			}
+			values[k] = v
+		}
+		json.NewEncoder(w).Encode(values)
+	case "OPTIONS":
+		put := map[string]interface{}{}
+		for k, v := range s.defaults[category] {
+			put[k] = map[string]interface{}{"default": v}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"actions": map[string]interface{}{"PUT": put}})
+	default:
+		http.Error(w, "", http.StatusMethodNotAllowed)
+	}
+}
+
+// get returns the current value of a setting, encoded as JSON.
+func (s *settingsStub) get(category, key string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, _ := json.Marshal(s.values[category][key])
+	return string(b)
+}
+
+func deepCopyJSON(t *testing.T, v interface{}) interface{} {
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var c interface{}
+	if err := json.Unmarshal(b, &c); err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// inventoryStub is a local stand-in for the groups and hosts endpoints of one AWX inventory.
+type inventoryStub struct {
+	*httptest.Server
+
+	mu          sync.Mutex
+	inventoryID int
+	kind        string
+	variables   string
+	nextID      int
+	groups      map[int]*stubGroup
+	hosts       map[int]*stubHost
+	requests    []string
+}
+
+type stubGroup struct {
+	Name      string
+	Variables string
+	Children  map[int]bool
+	Hosts     map[int]bool
+}
+
+type stubHost struct {
+	Name      string
+	Variables string
+	Enabled   bool
+}
+
+func newInventoryStub(inventoryID int) *inventoryStub {
+	stub := &inventoryStub{
+		inventoryID: inventoryID,
+		nextID:      100,
+		groups:      map[int]*stubGroup{},
+		hosts:       map[int]*stubHost{},
+	}
+	stub.Server = httptest.NewServer(http.HandlerFunc(stub.serve))
+	return stub
+}
+
+func (s *inventoryStub) addGroup(name, variables string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	s.groups[s.nextID] = &stubGroup{Name: name, Variables: variables, Children: map[int]bool{}, Hosts: map[int]bool{}}
+	return s.nextID
+}
+
+func (s *inventoryStub) addHost(name, variables string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	s.hosts[s.nextID] = &stubHost{Name: name, Variables: variables, Enabled: true}
+	return s.nextID
+}
+
+func (s *inventoryStub) groupID(name string) int {
+	for id, g := range s.groups {
+		if g.Name == name {
+			return id
+		}
+	}
+	return 0
+}
+
+func (s *inventoryStub) hostID(name string) int {
+	for id, h := range s.hosts {
+		if h.Name == name {
+			return id
+		}
+	}
+	return 0
+}
+
+// describe returns a sorted summary of the inventory, e.g. "group web children=[] hosts=[web1] vars={}".
+func (s *inventoryStub) describe() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	lines := []string{}
+	for _, g := range s.groups {
+		children, hosts := []string{}, []string{}
+		for id := range g.Children {
+			children = append(children, s.groups[id].Name)
+		}
+		for id := range g.Hosts {
+			hosts = append(hosts, s.hosts[id].Name)
+		}
+		sort.Strings(children)
+		sort.Strings(hosts)
+		lines = append(lines, fmt.Sprintf("group %s children=%v hosts=%v vars=%s", g.Name, children, hosts, stubCanonicalJSON(g.Variables)))
+	}
+	for _, h := range s.hosts {
+		lines = append(lines, fmt.Sprintf("host %s enabled=%t vars=%s", h.Name, h.Enabled, stubCanonicalJSON(h.Variables)))
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+func stubCanonicalJSON(document string) string {
+	parsed, err := parseJSONYaml(document)
+	if err != nil {
+		return document
+	}
+	b, _ := json.Marshal(parsed)
+	return string(b)
+}
+
+func (s *inventoryStub) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+
+	var body map[string]interface{}
+	if r.Method == "POST" || r.Method == "PATCH" {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v2/"), "/"), "/")
+	inventory := strconv.Itoa(s.inventoryID)
+
+	switch {
+	case len(parts) == 2 && parts[0] == "inventories" && parts[1] == inventory:
+		if r.Method == "PATCH" {
+			s.variables = body["variables"].(string)
+		}
+		s.write(w, map[string]interface{}{"id": s.inventoryID, "name": "stub", "kind": s.kind, "variables": s.variables,
+			"related": map[string]interface{}{"script": "/api/v2/inventories/" + inventory + "/script/"}})
+	case len(parts) == 3 && parts[0] == "inventories" && parts[1] == inventory && parts[2] == "script":
+		s.write(w, s.scriptJSON(r.URL.Query().Get("all") == "1"))
+	case len(parts) == 3 && parts[0] == "inventories" && parts[1] == inventory && parts[2] == "groups":
+		results := []interface{}{}
+		for _, id := range s.sortedGroupIDs() {
+			results = append(results, s.groupJSON(id))
+		}
+		s.writeList(w, results)
+	case len(parts) == 3 && parts[0] == "inventories" && parts[1] == inventory && parts[2] == "hosts":
+		results := []interface{}{}
+		for _, id := range s.sortedHostIDs() {
+			results = append(results, s.hostJSON(id))
+		}
+		s.writeList(w, results)
+	case len(parts) == 3 && parts[0] == "inventories" && parts[1] == inventory && parts[2] == "tree":
+		isChild := map[int]bool{}
+		for _, g := range s.groups {
+			for id := range g.Children {
+				isChild[id] = true
+			}
+		}
+		roots := []interface{}{}
+		for _, id := range s.sortedGroupIDs() {
+			if !isChild[id] {
+				roots = append(roots, s.treeJSON(id))
+			}
+		}
+		s.write(w, roots)
+	case len(parts) == 1 && parts[0] == "groups" && r.Method == "GET":
+		results := []interface{}{}
+		for _, id := range s.sortedGroupIDs() {
+			if s.matches(r, id, s.groups[id].Name) {
+				results = append(results, s.groupJSON(id))
+			}
+		}
+		s.writeList(w, results)
+	case len(parts) == 1 && parts[0] == "hosts" && r.Method == "GET":
+		results := []interface{}{}
+		for _, id := range s.sortedHostIDs() {
+			if s.matches(r, id, s.hosts[id].Name) {
+				results = append(results, s.hostJSON(id))
+			}
+		}
+		s.writeList(w, results)
+	case len(parts) == 1 && parts[0] == "groups" && r.Method == "POST":
+		s.nextID++
+		s.groups[s.nextID] = &stubGroup{Name: body["name"].(string), Variables: stubString(body["variables"]), Children: map[int]bool{}, Hosts: map[int]bool{}}
+		w.WriteHeader(http.StatusCreated)
+		s.write(w, s.groupJSON(s.nextID))
+	case len(parts) == 1 && parts[0] == "hosts" && r.Method == "POST":
+		s.nextID++
+		enabled, ok := body["enabled"].(bool)
+		s.hosts[s.nextID] = &stubHost{Name: body["name"].(string), Variables: stubString(body["variables"]), Enabled: enabled || !ok}
+		w.WriteHeader(http.StatusCreated)
+		s.write(w, s.hostJSON(s.nextID))
+	case len(parts) >= 2 && parts[0] == "groups":
+		id, _ := strconv.Atoi(parts[1])
+		g, ok := s.groups[id]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if len(parts) == 2 {
+			switch r.Method {
+			case "PATCH":
+				if v, ok := body["variables"]; ok {
+					g.Variables = stubString(v)
+				}
+				if v, ok := body["name"]; ok {
+					g.Name = v.(string)
+				}
+			case "DELETE":
+				delete(s.groups, id)
+				for _, other := range s.groups {
+					delete(other.Children, id)
+				}
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			s.write(w, s.groupJSON(id))
+			return
+		}
+		var related map[int]bool
+		var exists func(int) bool
+		switch parts[2] {
+		case "children":
+			related, exists = g.Children, func(i int) bool { _, ok := s.groups[i]; return ok && i != id }
+			if r.Method == "POST" && body["disassociate"] != true && s.descends(id, int(body["id"].(float64))) {
+				http.Error(w, `{"error": "Cyclical Group association."}`, http.StatusBadRequest)
+				return
+			}
+		case "hosts":
+			related, exists = g.Hosts, func(i int) bool { _, ok := s.hosts[i]; return ok }
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		s.serveRelated(w, r, body, related, exists, parts[2] == "children")
+	case len(parts) >= 2 && parts[0] == "hosts":
+		id, _ := strconv.Atoi(parts[1])
+		h, ok := s.hosts[id]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if len(parts) == 2 {
+			switch r.Method {
+			case "PATCH":
+				if v, ok := body["variables"]; ok {
+					h.Variables = stubString(v)
+				}
+				if v, ok := body["enabled"]; ok {
+					h.Enabled = v.(bool)
+				}
+			case "DELETE":
+				delete(s.hosts, id)
+				for _, g := range s.groups {
+					delete(g.Hosts, id)
+				}
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			s.write(w, s.hostJSON(id))
+			return
+		}
+		if parts[2] != "groups" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method == "POST" {
+			gid := int(body["id"].(float64))
+			g, ok := s.groups[gid]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			if body["disassociate"] == true {
+				delete(g.Hosts, id)
+			} else {
+				g.Hosts[id] = true
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		results := []interface{}{}
+		for _, gid := range s.sortedGroupIDs() {
+			if s.groups[gid].Hosts[id] {
+				results = append(results, s.groupJSON(gid))
+			}
+		}
+		s.writeList(w, results)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// descends returns true if the group is the given ancestor or one of its descendants, like AWX refusing cyclical
+// group associations.
+func (s *inventoryStub) descends(id, ancestor int) bool {
+	if id == ancestor {
+		return true
+	}
+	g, ok := s.groups[ancestor]
+	if !ok {
+		return false
+	}
+	for child := range g.Children {
+		if s.descends(id, child) {
+			return true
+		}
+	}
+	return false
+}
+
+// matches applies the id, name and inventory filters of a list request.
+func (s *inventoryStub) matches(r *http.Request, id int, name string) bool {
+	query := r.URL.Query()
+	if v := query.Get("id"); v != "" && v != strconv.Itoa(id) {
+		return false
+	}
+	if v := query.Get("name"); v != "" && v != name {
+		return false
+	}
+	if v := query.Get("inventory"); v != "" && v != strconv.Itoa(s.inventoryID) {
+		return false
+	}
+	return true
+}
+
+func (s *inventoryStub) serveRelated(w http.ResponseWriter, r *http.Request, body map[string]interface{}, related map[int]bool, exists func(int) bool, groups bool) {
+	if r.Method == "POST" {
+		id := int(body["id"].(float64))
+		if !exists(id) {
+			http.NotFound(w, r)
+			return
+		}
+		if body["disassociate"] == true {
+			delete(related, id)
+		} else {
+			related[id] = true
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	ids := []int{}
+	for id := range related {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	results := []interface{}{}
+	for _, id := range ids {
+		if groups {
+			results = append(results, s.groupJSON(id))
+		} else {
+			results = append(results, s.hostJSON(id))
+		}
+	}
+	s.writeList(w, results)
+}
+
+func (s *inventoryStub) groupJSON(id int) map[string]interface{} {
+	g := s.groups[id]
+	return map[string]interface{}{"id": id, "name": g.Name, "inventory": s.inventoryID, "variables": g.Variables}
+}
+
+func (s *inventoryStub) hostJSON(id int) map[string]interface{} {
+	h := s.hosts[id]
+	return map[string]interface{}{"id": id, "name": h.Name, "inventory": s.inventoryID, "variables": h.Variables, "enabled": h.Enabled}
+}
+
+func (s *inventoryStub) treeJSON(id int) map[string]interface{} {
+	children := []interface{}{}
+	ids := []int{}
+	for child := range s.groups[id].Children {
+		ids = append(ids, child)
+	}
+	sort.Ints(ids)
+	for _, child := range ids {
+		children = append(children, s.treeJSON(child))
+	}
+	return map[string]interface{}{"id": id, "name": s.groups[id].Name, "children": children}
+}
+
+// scriptJSON mimics /inventories/{id}/script/?hostvars=1, disabled hosts are only listed with all.
+func (s *inventoryStub) scriptJSON(all bool) map[string]interface{} {
+	listed := func(id int) bool { return all || s.hosts[id].Enabled }
+	hostvars := map[string]interface{}{}
+	for id, h := range s.hosts {
+		if listed(id) {
+			if vars, err := parseJSONYaml(h.Variables); err == nil {
+				hostvars[h.Name] = vars
+			}
+		}
+	}
+	script := map[string]interface{}{"_meta": map[string]interface{}{"hostvars": hostvars}}
+	grouped := map[int]bool{}
+	for _, g := range s.groups {
+		hosts, children := []string{}, []string{}
+		for id := range g.Hosts {
+			grouped[id] = true
+			if listed(id) {
+				hosts = append(hosts, s.hosts[id].Name)
+			}
+		}
+		for id := range g.Children {
+			children = append(children, s.groups[id].Name)
+		}
+		vars, _ := parseJSONYaml(g.Variables)
+		script[g.Name] = map[string]interface{}{"hosts": hosts, "children": children, "vars": vars}
+	}
+	ungrouped := []string{}
+	for id, h := range s.hosts {
+		if !grouped[id] && listed(id) {
+			ungrouped = append(ungrouped, h.Name)
+		}
+	}
+	vars, _ := parseJSONYaml(s.variables)
+	script["all"] = map[string]interface{}{"hosts": ungrouped, "vars": vars}
+	return script
+}
+
+func (s *inventoryStub) sortedGroupIDs() []int {
+	ids := []int{}
+	for id := range s.groups {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func (s *inventoryStub) sortedHostIDs() []int {
+	ids := []int{}
+	for id := range s.hosts {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func (s *inventoryStub) write(w http.ResponseWriter, v interface{}) {
+	json.NewEncoder(w).Encode(v)
+}
+
+func (s *inventoryStub) writeList(w http.ResponseWriter, results []interface{}) {
+	s.write(w, map[string]interface{}{"count": len(results), "next": nil, "previous": nil, "results": results})
+}
+
+func stubString(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	return ""
+}
+
+// constructedInventoryStub is a local stand-in for the ping and constructed inventories endpoints of AWX, the regular
+// inventories 1 to 3 are available as input inventories.
+type constructedInventoryStub struct {
+	*httptest.Server
+
+	mu          sync.Mutex
+	version     string
+	nextID      int
+	inventories map[int]map[string]interface{}
+	inputs      map[int][]int
+}
+
+func newConstructedInventoryStub(version string) *constructedInventoryStub {
+	stub := &constructedInventoryStub{
+		version:     version,
+		nextID:      10,
+		inventories: map[int]map[string]interface{}{},
+		inputs:      map[int][]int{},
+	}
+	stub.Server = httptest.NewServer(http.HandlerFunc(stub.serve))
+	return stub
+}
+
+func (s *constructedInventoryStub) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var body map[string]interface{}
+	if r.Method == "POST" || r.Method == "PATCH" {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v2/"), "/"), "/")
+	supported := compareVersions(s.version, "22.0.0") >= 0
+
+	switch {
+	case len(parts) == 1 && parts[0] == "ping":
+		json.NewEncoder(w).Encode(map[string]interface{}{"version": s.version})
+	case parts[0] == "constructed_inventories" && !supported:
+		http.NotFound(w, r)
+	case len(parts) == 1 && parts[0] == "constructed_inventories" && r.Method == "POST":
+		s.nextID++
+		body["id"] = s.nextID
+		body["kind"] = "constructed"
+		s.inventories[s.nextID] = body
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(body)
+	case len(parts) == 2 && parts[0] == "constructed_inventories":
+		id, _ := strconv.Atoi(parts[1])
+		inventory, ok := s.inventories[id]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		switch r.Method {
+		case "PATCH":
+			for key, value := range body {
+				inventory[key] = value
+			}
+		case "DELETE":
+			delete(s.inventories, id)
+			delete(s.inputs, id)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		json.NewEncoder(w).Encode(inventory)
+	case len(parts) == 3 && parts[0] == "inventories" && parts[2] == "input_inventories":
+		id, _ := strconv.Atoi(parts[1])
+		if _, ok := s.inventories[id]; !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method == "POST" {
+			input := int(body["id"].(float64))
+			if input < 1 || input > 3 {
+				http.NotFound(w, r)
+				return
+			}
+			inputs := []int{}
+			for _, existing := range s.inputs[id] {
+				if existing != input {
+					inputs = append(inputs, existing)
+				}
+			}
+			if body["disassociate"] != true {
+				inputs = append(inputs, input)
+			}
+			s.inputs[id] = inputs
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		results := []interface{}{}
+		for _, input := range s.inputs[id] {
+			results = append(results, map[string]interface{}{"id": input, "name": fmt.Sprintf("inventory-%d", input)})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"count": len(results), "next": nil, "previous": nil, "results": results})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// instanceStub is a local stand-in for the ping, instances and receptor addresses endpoints of AWX. From AWX 24 on,
+// the peers are receptor address IDs and every instance with a listener port gets a canonical address.
+type instanceStub struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	version   string
+	nextID    int
+	instances map[int]map[string]interface{}
+	addresses map[int]map[string]interface{}
+}
+
+func newInstanceStub(version string) *instanceStub {
+	stub := &instanceStub{
+		version:   version,
+		nextID:    10,
+		instances: map[int]map[string]interface{}{},
+		addresses: map[int]map[string]interface{}{},
+	}
+	stub.Server = httptest.NewServer(http.HandlerFunc(stub.serve))
+	return stub
+}
+
+func (s *instanceStub) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var body map[string]interface{}
+	if r.Method == "POST" || r.Method == "PATCH" {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/v2/"), "/"), "/")
+	addresses := compareVersions(s.version, "24.0.0") >= 0
+
+	switch {
+	case len(parts) == 1 && parts[0] == "ping":
+		json.NewEncoder(w).Encode(map[string]interface{}{"version": s.version})
+	case len(parts) == 1 && parts[0] == "instances" && r.Method == "POST":
+		if !s.validPeers(w, body["peers"], addresses) {
+			return
+		}
+		s.nextID++
+		body["id"] = s.nextID
+		s.instances[s.nextID] = body
+		if addresses {
+			s.nextID++
+			s.addresses[s.nextID] = map[string]interface{}{"id": s.nextID, "address": body["hostname"],
+				"port": body["listener_port"], "canonical": true, "instance": body["id"]}
+		}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(body)
+	case len(parts) == 1 && (parts[0] == "instances" || parts[0] == "receptor_addresses" && addresses):
+		objects, key, filter := s.instances, "hostname", r.URL.Query().Get("hostname")
+		if parts[0] == "receptor_addresses" {
+			objects, key, filter = s.addresses, "address", r.URL.Query().Get("address")
+		}
+		results := []interface{}{}
+		for _, id := range stubSortedIDs(objects) {
+			if filter == "" || objects[id][key] == filter {
+				results = append(results, objects[id])
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"count": len(results), "next": nil, "previous": nil, "results": results})
+	case len(parts) == 2 && parts[0] == "instances":
+		id, _ := strconv.Atoi(parts[1])
+		instance, ok := s.instances[id]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if r.Method == "PATCH" {
+			if peers, ok := body["peers"]; ok && !s.validPeers(w, peers, addresses) {
+				return
+			}
+			for key, value := range body {
+				instance[key] = value
+			}
+		}
+		json.NewEncoder(w).Encode(instance)
+	case len(parts) == 2 && parts[0] == "receptor_addresses" && addresses:
+		id, _ := strconv.Atoi(parts[1])
+		address, ok := s.addresses[id]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		json.NewEncoder(w).Encode(address)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// validPeers refuses the peers of the shape the version does not expect, hostnames or receptor address IDs.
+func (s *instanceStub) validPeers(w http.ResponseWriter, peers interface{}, addresses bool) bool {
+	list, _ := peers.([]interface{})
+	for _, peer := range list {
+		id, isID := peer.(float64)
+		if _, exists := s.addresses[int(id)]; isID != addresses || isID && !exists {
+			http.Error(w, fmt.Sprintf(`{"peers": ["Invalid peer %v."]}`, peer), http.StatusBadRequest)
+			return false
+		}
+	}
+	return true
+}
+
+func stubSortedIDs(objects map[int]map[string]interface{}) []int {
+	ids := []int{}
+	for id := range objects {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids
+}
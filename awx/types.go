@@ -0,0 +1,151 @@
+package awx
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Types of the awx api objects not (yet) modeled by awx-go.
+
+// Schedule represents the awx api schedule.
+type Schedule struct {
+	ID                 int                    `json:"id"`
+	Name               string                 `json:"name"`
+	Description        string                 `json:"description"`
+	UnifiedJobTemplate int                    `json:"unified_job_template"`
+	Enabled            bool                   `json:"enabled"`
+	Rrule              string                 `json:"rrule"`
+	Timezone           string                 `json:"timezone"`
+	ExtraData          map[string]interface{} `json:"extra_data"`
+	Inventory          *int                   `json:"inventory"`
+	ScmBranch          *string                `json:"scm_branch"`
+	JobType            *string                `json:"job_type"`
+	JobTags            *string                `json:"job_tags"`
+	SkipTags           *string                `json:"skip_tags"`
+	Limit              *string                `json:"limit"`
+	DiffMode           *bool                  `json:"diff_mode"`
+	Verbosity          *int                   `json:"verbosity"`
+	NextRun            *time.Time             `json:"next_run"`
+}
+
+// NotificationTemplate represents the awx api notification template.
+type NotificationTemplate struct {
+	ID                        int                    `json:"id"`
+	Name                      string                 `json:"name"`
+	Description               string                 `json:"description"`
+	Organization              int                    `json:"organization"`
+	NotificationType          string                 `json:"notification_type"`
+	NotificationConfiguration map[string]interface{} `json:"notification_configuration"`
+	Messages                  map[string]interface{} `json:"messages"`
+}
+
+// Label represents the awx api label.
+type Label struct {
+	ID           int    `json:"id"`
+	Name         string `json:"name"`
+	Organization int    `json:"organization"`
+}
+
+// InstanceGroup represents the awx api instance group (the awx-go one only carries the ping summary).
+type InstanceGroup struct {
+	ID                       int      `json:"id"`
+	Name                     string   `json:"name"`
+	IsContainerGroup         bool     `json:"is_container_group"`
+	Credential               *int     `json:"credential"`
+	PodSpecOverride          string   `json:"pod_spec_override"`
+	PolicyInstancePercentage int      `json:"policy_instance_percentage"`
+	PolicyInstanceMinimum    int      `json:"policy_instance_minimum"`
+	PolicyInstanceList       []string `json:"policy_instance_list"`
+	MaxConcurrentJobs        int      `json:"max_concurrent_jobs"`
+	MaxForks                 int      `json:"max_forks"`
+}
+
+// ExecutionEnvironment represents the awx api execution environment.
+type ExecutionEnvironment struct {
+	ID           int    `json:"id"`
+	Name         string `json:"name"`
+	Description  string `json:"description"`
+	Image        string `json:"image"`
+	Pull         string `json:"pull"`
+	Credential   *int   `json:"credential"`
+	Organization *int   `json:"organization"`
+}
+
+// ConstructedInventory represents the awx api constructed inventory (AWX 22+).
+type ConstructedInventory struct {
+	ID                 int    `json:"id"`
+	Name               string `json:"name"`
+	Description        string `json:"description"`
+	Organization       int    `json:"organization"`
+	Kind               string `json:"kind"`
+	Variables          string `json:"variables"`
+	SourceVars         string `json:"source_vars"`
+	Limit              string `json:"limit"`
+	UpdateCacheTimeout int    `json:"update_cache_timeout"`
+	Verbosity          int    `json:"verbosity"`
+}
+
+// Instance represents the awx api instance (a node of the receptor mesh).
+type Instance struct {
+	ID                    int               `json:"id"`
+	Hostname              string            `json:"hostname"`
+	NodeType              string            `json:"node_type"`
+	NodeState             string            `json:"node_state"`
+	ListenerPort          *int              `json:"listener_port"`
+	Peers                 []json.RawMessage `json:"peers"`
+	PeersFromControlNodes bool              `json:"peers_from_control_nodes"`
+	Enabled               bool              `json:"enabled"`
+	ManagedByPolicy       bool              `json:"managed_by_policy"`
+	CapacityAdjustment    string            `json:"capacity_adjustment"`
+	Capacity              int               `json:"capacity"`
+}
+
+// ReceptorAddress represents the awx api receptor address (AWX 24+), the peers of an instance are addresses.
+type ReceptorAddress struct {
+	ID        int    `json:"id"`
+	Address   string `json:"address"`
+	Port      int    `json:"port"`
+	Canonical bool   `json:"canonical"`
+	Instance  int    `json:"instance"`
+}
+
+// Application represents the awx api OAuth2 application.
+type Application struct {
+	ID                     int    `json:"id"`
+	Name                   string `json:"name"`
+	Description            string `json:"description"`
+	Organization           int    `json:"organization"`
+	AuthorizationGrantType string `json:"authorization_grant_type"`
+	ClientType             string `json:"client_type"`
+	RedirectURIs           string `json:"redirect_uris"`
+	SkipAuthorization      bool   `json:"skip_authorization"`
+	ClientID               string `json:"client_id"`
+	ClientSecret           string `json:"client_secret"`
+}
+
+// Token represents the awx api OAuth2 access token.
+type Token struct {
+	ID           int     `json:"id"`
+	Description  string  `json:"description"`
+	User         int     `json:"user"`
+	Application  *int    `json:"application"`
+	Scope        string  `json:"scope"`
+	Token        string  `json:"token"`
+	RefreshToken *string `json:"refresh_token"`
+	Expires      string  `json:"expires"`
+}
+
+// User represents the awx api user, including the fields awx-go does not decode.
+type User struct {
+	ID              int                 `json:"id"`
+	Username        string              `json:"username"`
+	FirstName       string              `json:"first_name"`
+	LastName        string              `json:"last_name"`
+	Email           string              `json:"email"`
+	IsSuperuser     bool                `json:"is_superuser"`
+	IsSystemAuditor bool                `json:"is_system_auditor"`
+	LdapDn          string              `json:"ldap_dn"`
+	ExternalAccount *string             `json:"external_account"`
+	LastLogin       *string             `json:"last_login"`
+	Auth            []map[string]string `json:"auth"`
+}
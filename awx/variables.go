@@ -0,0 +1,190 @@
+package awx
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// expandVariables returns the variables document to send to AWX, encoded from mapKey (values are JSON) when it is
+// set, key (JSON or YAML) otherwise.
+func expandVariables(d *schema.ResourceData, key, mapKey string) (string, error) {
+	raw, ok := d.GetOk(mapKey)
+	if !ok {
+		return d.Get(key).(string), nil
+	}
+	variables := map[string]interface{}{}
+	for name, value := range raw.(map[string]interface{}) {
+		var decoded interface{}
+		if err := json.Unmarshal([]byte(value.(string)), &decoded); err != nil {
+			return "", fmt.Errorf("Value of %s in %s is not valid JSON: %s", name, mapKey, err)
+		}
+		variables[name] = decoded
+	}
+	b, err := json.Marshal(variables)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// flattenVariables refreshes key or mapKey, the one in use, from the variables document returned by AWX.
+func flattenVariables(d *schema.ResourceData, key, mapKey, document string) error {
+	if _, ok := d.GetOk(mapKey); !ok {
+		d.Set(key, document)
+		d.Set(mapKey, nil)
+		return nil
+	}
+	parsed, err := parseJSONYaml(document)
+	if err != nil {
+		return fmt.Errorf("Cannot decode %s returned by AWX: %s", key, err)
+	}
+	variables, ok := parsed.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("Expected %s returned by AWX to be a mapping, got %q", key, document)
+	}
+	values := map[string]interface{}{}
+	for name, value := range variables {
+		b, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		values[name] = string(b)
+	}
+	d.Set(key, "")
+	d.Set(mapKey, values)
+	return nil
+}
+
+// managedVariableKeys returns the top-level keys of the variables owned by Terraform, nil when it owns the whole
+// document.
+func managedVariableKeys(d resourceGetter) []string {
+	raw, ok := d.GetOk("variables_managed_keys")
+	if !ok {
+		return nil
+	}
+	keys := []string{}
+	for _, key := range raw.(*schema.Set).List() {
+		keys = append(keys, key.(string))
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// mergeManagedVariables merges the managed keys of document into the variables currently set in AWX, managed keys
+// missing from document are removed. Without managed keys, document is returned as is.
+func mergeManagedVariables(d resourceGetter, document, current string) (string, error) {
+	keys := managedVariableKeys(d)
+	if keys == nil {
+		return document, nil
+	}
+	desired, err := parseJSONYaml(document)
+	if err != nil {
+		return "", err
+	}
+	parsed, err := parseJSONYaml(current)
+	if err != nil {
+		return "", fmt.Errorf("Cannot decode variables returned by AWX: %s", err)
+	}
+	variables, ok := parsed.(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("Expected variables returned by AWX to be a mapping, got %q", current)
+	}
+	for _, key := range keys {
+		if value, ok := desired.(map[string]interface{})[key]; ok {
+			variables[key] = value
+		} else {
+			delete(variables, key)
+		}
+	}
+	b, err := json.Marshal(variables)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// filterManagedVariables keeps the managed keys of the variables returned by AWX, the other keys belong to someone
+// else (inventory sources, fact caching, ...). Without managed keys, document is returned as is.
+func filterManagedVariables(d resourceGetter, document string) (string, error) {
+	keys := managedVariableKeys(d)
+	if keys == nil {
+		return document, nil
+	}
+	parsed, err := parseJSONYaml(document)
+	if err != nil {
+		return "", fmt.Errorf("Cannot decode variables returned by AWX: %s", err)
+	}
+	variables, ok := parsed.(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("Expected variables returned by AWX to be a mapping, got %q", document)
+	}
+	managed := map[string]interface{}{}
+	for _, key := range keys {
+		if value, ok := variables[key]; ok {
+			managed[key] = value
+		}
+	}
+	b, err := json.Marshal(managed)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// resourceVariablesCustomizeDiff checks only managed keys are set in the variables, when some are declared.
+func resourceVariablesCustomizeDiff(d *schema.ResourceDiff, m interface{}) error {
+	keys := managedVariableKeys(d)
+	if keys == nil {
+		return nil
+	}
+	names := []string{}
+	if raw, ok := d.GetOk("variables_map"); ok {
+		for name := range raw.(map[string]interface{}) {
+			names = append(names, name)
+		}
+	} else if d.NewValueKnown("variables") {
+		// Invalid documents are reported by the validation of variables
+		if parsed, err := parseJSONYaml(d.Get("variables").(string)); err == nil {
+			if variables, ok := parsed.(map[string]interface{}); ok {
+				for name := range variables {
+					names = append(names, name)
+				}
+			}
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !stringInSlice(name, keys) {
+			return fmt.Errorf("Variable %s is not in variables_managed_keys (%s)", name, strings.Join(keys, ", "))
+		}
+	}
+	return nil
+}
+
+// validateJSONValues checks every value of a map is JSON encoded.
+func validateJSONValues(v interface{}, k string) (ws []string, errors []error) {
+	for key, value := range v.(map[string]interface{}) {
+		var decoded interface{}
+		if err := json.Unmarshal([]byte(value.(string)), &decoded); err != nil {
+			errors = append(errors, fmt.Errorf("%q: value of %s is not valid JSON (strings must be quoted, use jsonencode): %s", k, key, err))
+		}
+	}
+	return
+}
+
+// suppressEquivalentJSONValue ignores the formatting differences of the JSON values of a map.
+func suppressEquivalentJSONValue(k, old, new string, d *schema.ResourceData) bool {
+	if strings.HasSuffix(k, ".%") || old == "" || new == "" {
+		return false
+	}
+	var o, n interface{}
+	if json.Unmarshal([]byte(old), &o) != nil || json.Unmarshal([]byte(new), &n) != nil {
+		return false
+	}
+	return reflect.DeepEqual(o, n)
+}
@@ -0,0 +1,114 @@
+package awx
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func testVariablesResourceData(t *testing.T, raw map[string]interface{}) *schema.ResourceData {
+	return schema.TestResourceDataRaw(t, resourceHostObject().Schema, raw)
+}
+
+func TestExpandVariables(t *testing.T) {
+	d := testVariablesResourceData(t, map[string]interface{}{
+		"name":         "web1",
+		"inventory_id": 1,
+		"variables_map": map[string]interface{}{
+			"ansible_port": "22",
+			"tags":         `["web", "front"]`,
+			"proxy":        `{"host": "proxy", "port": 3128}`,
+		},
+	})
+	document, err := expandVariables(d, "variables", "variables_map")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"ansible_port":22,"proxy":{"host":"proxy","port":3128},"tags":["web","front"]}`
+	if document != expected {
+		t.Errorf("expandVariables() = %s, expected %s", document, expected)
+	}
+
+	d = testVariablesResourceData(t, map[string]interface{}{
+		"name":         "web1",
+		"inventory_id": 1,
+		"variables":    "ansible_port: 22\n",
+	})
+	if document, _ := expandVariables(d, "variables", "variables_map"); document != "ansible_port: 22\n" {
+		t.Errorf("expandVariables() = %q, expected the variables as is", document)
+	}
+}
+
+func TestFlattenVariables(t *testing.T) {
+	d := testVariablesResourceData(t, map[string]interface{}{
+		"name":          "web1",
+		"inventory_id":  1,
+		"variables_map": map[string]interface{}{"ansible_port": "22"},
+	})
+	if err := flattenVariables(d, "variables", "variables_map", "---\nansible_port: 2222\ntags: [web]\n"); err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]interface{}{"ansible_port": "2222", "tags": `["web"]`}
+	if values := d.Get("variables_map").(map[string]interface{}); !reflect.DeepEqual(values, expected) {
+		t.Errorf("variables_map = %v, expected %v", values, expected)
+	}
+	if variables := d.Get("variables").(string); variables != "" {
+		t.Errorf("variables = %q, expected it to be empty", variables)
+	}
+	if err := flattenVariables(d, "variables", "variables_map", "- not\n- a mapping\n"); err == nil {
+		t.Error("Expected an error for variables that are not a mapping")
+	}
+
+	d = testVariablesResourceData(t, map[string]interface{}{
+		"name":         "web1",
+		"inventory_id": 1,
+		"variables":    "ansible_port: 22\n",
+	})
+	if err := flattenVariables(d, "variables", "variables_map", `{"ansible_port": 2222}`); err != nil {
+		t.Fatal(err)
+	}
+	if variables := d.Get("variables").(string); variables != `{"ansible_port": 2222}` {
+		t.Errorf("variables = %q, expected the document as is", variables)
+	}
+}
+
+func TestMergeManagedVariables(t *testing.T) {
+	d := testVariablesResourceData(t, map[string]interface{}{
+		"name":                   "web1",
+		"inventory_id":           1,
+		"variables_managed_keys": []interface{}{"ntp_servers", "role", "legacy"},
+	})
+	current := "---\nrole: db\nlegacy: true\nansible_facts:\n  os: linux\n"
+	document, err := mergeManagedVariables(d, `{"role": "web", "ntp_servers": ["ntp1"]}`, current)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{"ansible_facts":{"os":"linux"},"ntp_servers":["ntp1"],"role":"web"}`
+	if document != expected {
+		t.Errorf("mergeManagedVariables() = %s, expected %s", document, expected)
+	}
+
+	d = testVariablesResourceData(t, map[string]interface{}{"name": "web1", "inventory_id": 1})
+	if document, _ := mergeManagedVariables(d, "role: web\n", current); document != "role: web\n" {
+		t.Errorf("mergeManagedVariables() = %q, expected the document as is", document)
+	}
+}
+
+func TestFilterManagedVariables(t *testing.T) {
+	d := testVariablesResourceData(t, map[string]interface{}{
+		"name":                   "web1",
+		"inventory_id":           1,
+		"variables_managed_keys": []interface{}{"ntp_servers", "role"},
+	})
+	document, err := filterManagedVariables(d, "---\nrole: db\nansible_facts:\n  os: linux\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `{"role":"db"}`; document != expected {
+		t.Errorf("filterManagedVariables() = %s, expected %s", document, expected)
+	}
+	if _, err := filterManagedVariables(d, "[1, 2]"); err == nil {
+		t.Error("Expected an error for variables that are not a mapping")
+	}
+}
@@ -0,0 +1,93 @@
+package awx
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// serverVersion returns the version reported by the AWX ping endpoint, it is only requested once.
+func (c *Client) serverVersion() (string, error) {
+	c.versionOnce.Do(func() {
+		ping, err := c.PingService.Ping()
+		if err != nil {
+			c.versionErr = fmt.Errorf("Unable to detect the AWX version: %s", err)
+			return
+		}
+		c.version = ping.Version
+	})
+	return c.version, c.versionErr
+}
+
+// supportsExecutionEnvironments reports whether the server replaced custom virtualenvs by execution environments.
+func (c *Client) supportsExecutionEnvironments() (bool, error) {
+	return c.supportsEndpoint("18.0.0", "/api/v2/execution_environments/")
+}
+
+// supportsConstructedInventories reports whether the server has constructed inventories (AWX 22+).
+func (c *Client) supportsConstructedInventories() (bool, error) {
+	return c.supportsEndpoint("22.0.0", "/api/v2/constructed_inventories/")
+}
+
+// supportsReceptorAddresses reports whether the peers of the instances are receptor addresses instead of hostnames
+// (AWX 24+).
+func (c *Client) supportsReceptorAddresses() (bool, error) {
+	return c.supportsEndpoint("24.0.0", "/api/v2/receptor_addresses/")
+}
+
+// supportsEndpoint reports whether the server has a feature added by AWX since. Tower 3.x has none of them, versions
+// in between are either an older AWX or automation controller 4.x and the endpoint of the feature is probed.
+func (c *Client) supportsEndpoint(since, endpoint string) (bool, error) {
+	version, err := c.serverVersion()
+	if err != nil {
+		return false, err
+	}
+	if compareVersions(version, since) >= 0 {
+		return true, nil
+	}
+	if compareVersions(version, "4.0.0") < 0 {
+		return false, nil
+	}
+	var res struct{}
+	err = c.apiGet(endpoint, &res, map[string]string{"page_size": "1"})
+	if isNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// compareVersions compares two dotted versions numerically, ignoring any suffix such as "-dev" or "+build".
+func compareVersions(a, b string) int {
+	pa, pb := versionParts(a), versionParts(b)
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		var x, y int
+		if i < len(pa) {
+			x = pa[i]
+		}
+		if i < len(pb) {
+			y = pb[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+func versionParts(version string) []int {
+	if i := strings.IndexAny(version, "-+ "); i >= 0 {
+		version = version[:i]
+	}
+	var parts []int
+	for _, p := range strings.Split(version, ".") {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			break
+		}
+		parts = append(parts, n)
+	}
+	return parts
+}
@@ -0,0 +1,26 @@
+package awx
+
+import (
+	"testing"
+)
+
+func TestCompareVersions(t *testing.T) {
+	cases := []struct {
+		a, b     string
+		expected int
+	}{
+		{"18.0.0", "18.0.0", 0},
+		{"18.0", "18.0.0", 0},
+		{"17.1.0", "18.0.0", -1},
+		{"21.3.0", "18.0.0", 1},
+		{"3.8.5", "4.0.0", -1},
+		{"10.0.0", "9.9.9", 1},
+		{"19.5.1.dev12+g8a8e4b0", "19.5.1", 0},
+		{"22.0.0-rc1", "21.99.0", 1},
+	}
+	for _, c := range cases {
+		if got := compareVersions(c.a, c.b); got != c.expected {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", c.a, c.b, got, c.expected)
+		}
+	}
+}
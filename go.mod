@@ -5,5 +5,6 @@ go 1.12
 require (
 	github.com/davidfischer-ch/awx-go v0.2.3
 	github.com/hashicorp/terraform v0.12.6
+	golang.org/x/crypto v0.0.0-20190701094942-4def268fd1a4
 	gopkg.in/yaml.v2 v2.2.5
 )